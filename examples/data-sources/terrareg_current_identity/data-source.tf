@@ -0,0 +1,8 @@
+data "terrareg_current_identity" "this" { }
+
+output "can_manage_example_namespace" {
+  value = (
+    data.terrareg_current_identity.this.site_admin ||
+    lookup(data.terrareg_current_identity.this.namespace_permissions, "example-namespace", "") == "FULL"
+  )
+}
@@ -0,0 +1,14 @@
+data "terrareg_namespace" "platform" {
+  name = "platform"
+}
+
+check "platform_namespace_trusted" {
+  assert {
+    condition     = data.terrareg_namespace.platform.trusted
+    error_message = "The platform namespace must be trusted."
+  }
+}
+
+output "platform_module_count" {
+  value = data.terrareg_namespace.platform.module_count
+}
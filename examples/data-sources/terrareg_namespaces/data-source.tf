@@ -0,0 +1,9 @@
+# All trusted platform namespaces
+data "terrareg_namespaces" "platform" {
+  name_regex   = "^platform-"
+  trusted_only = true
+}
+
+output "platform_namespaces" {
+  value = data.terrareg_namespaces.platform.namespaces[*].name
+}
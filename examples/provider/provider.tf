@@ -4,5 +4,14 @@ provider "terrareg" {
   # Example with Terrareg running locally
   #url = "http://localhost:5000"
 
-  api_key = "my-secret-admin-password"
+  admin_token = "my-secret-admin-password"
+
+  # Optionally, provide upload/publish API keys, which are
+  # used instead of the admin token for module versions
+  #upload_api_key  = "my-upload-api-key"
+  #publish_api_key = "my-publish-api-key"
 }
+
+# Alternatively, configure the provider using the
+# TERRAREG_URL and TERRAREG_ADMIN_TOKEN environment variables
+# provider "terrareg" {}
@@ -4,3 +4,11 @@ resource "terrareg_namespace" "example" {
   # Optional display name
   display_name = "My Example Namespace"
 }
+
+resource "terrareg_namespace" "disposable" {
+  name = "disposable-namespace"
+
+  # Delete any modules in the namespace, including those created outside
+  # of Terraform, when the namespace is destroyed
+  force_destroy = true
+}
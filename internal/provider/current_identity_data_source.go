@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CurrentIdentityDataSource{}
+
+func NewCurrentIdentityDataSource() datasource.DataSource {
+	return &CurrentIdentityDataSource{}
+}
+
+// CurrentIdentityDataSource defines the data source implementation.
+type CurrentIdentityDataSource struct {
+	client *terrareg.TerraregClient
+}
+
+// CurrentIdentityDataSourceModel describes the data source data model.
+type CurrentIdentityDataSourceModel struct {
+	Id                   types.String      `tfsdk:"id"`
+	Authenticated        types.Bool        `tfsdk:"authenticated"`
+	ReadAccess           types.Bool        `tfsdk:"read_access"`
+	SiteAdmin            types.Bool        `tfsdk:"site_admin"`
+	NamespacePermissions map[string]string `tfsdk:"namespace_permissions"`
+}
+
+func (d *CurrentIdentityDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_identity"
+}
+
+func (d *CurrentIdentityDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Data source for obtaining the authentication status and permissions of the credentials used by the provider",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Internal ID",
+			},
+			"authenticated": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the provider credentials are valid",
+			},
+			"read_access": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the provider credentials have read access to Terrareg",
+			},
+			"site_admin": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the provider credentials have site admin permissions",
+			},
+			"namespace_permissions": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "Map of namespace names to the permission type (`FULL` or `MODIFY`) that the provider credentials have on the namespace. Site admins have full access to all namespaces, which are not included.",
+			},
+		},
+	}
+}
+
+func (d *CurrentIdentityDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*terrareg.TerraregClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *terrareg.TerraregClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *CurrentIdentityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, d.client)
+	defer done()
+
+	var data CurrentIdentityDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	authStatus, err := d.client.GetAuthenticationStatus(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read authentication status", err)
+		return
+	}
+
+	data.Authenticated = types.BoolValue(authStatus.Authenticated)
+	data.ReadAccess = types.BoolValue(authStatus.ReadAccess)
+	data.SiteAdmin = types.BoolValue(authStatus.SiteAdmin)
+	data.NamespacePermissions = authStatus.NamespacePermissions
+	if data.NamespacePermissions == nil {
+		data.NamespacePermissions = map[string]string{}
+	}
+
+	// Create fake ID, required by Terraform
+	data.Id = types.StringValue("this")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCurrentIdentityDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: buildTestProviderConfig(testAccCurrentIdentityDataSourceConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_current_identity.this", "authenticated", "true"),
+					resource.TestCheckResourceAttr("data.terrareg_current_identity.this", "site_admin", "true"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCurrentIdentityDataSourceConfig = `
+data "terrareg_current_identity" "this" { }
+`
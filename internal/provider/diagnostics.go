@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// addClientError adds an error diagnostic for an error returned by the
+// Terrareg client, including the details of the request and the error
+// message returned by Terrareg, where available.
+func addClientError(diags *diag.Diagnostics, action string, err error) {
+	var apiErr *terrareg.APIError
+	if !errors.As(err, &apiErr) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", action, err))
+		return
+	}
+
+	detail := fmt.Sprintf(
+		"Unable to %s, got error: %s\n\nRequest: %s %s\nHTTP Status: %d",
+		action, apiErr.Err, apiErr.Method, apiErr.URL, apiErr.StatusCode,
+	)
+	if apiErr.Message != "" {
+		detail += fmt.Sprintf("\nTerrareg Message: %s", apiErr.Message)
+	}
+	diags.AddError("Client Error", detail)
+}
+
+// collectUnknownFields returns a context that collects the fields returned
+// by Terrareg for requests made using it that are not supported by the
+// provider, and a function, to be deferred, that adds a single warning
+// diagnostic for any fields that have not already been reported.
+//
+// Each operation collects its own fields, so that the warning is reported
+// by the operation that received them, e.g.
+//
+//	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+//	defer done()
+func collectUnknownFields(ctx context.Context, diags *diag.Diagnostics, client *terrareg.TerraregClient) (context.Context, func()) {
+	ctx = terrareg.CollectUnknownFields(ctx)
+	return ctx, func() {
+		addUnknownFieldsWarning(ctx, diags, client)
+	}
+}
+
+// addUnknownFieldsWarning adds a single warning diagnostic for the unknown
+// fields collected by the context that have not already been reported.
+func addUnknownFieldsWarning(ctx context.Context, diags *diag.Diagnostics, client *terrareg.TerraregClient) {
+	if client == nil {
+		return
+	}
+	fields := client.TakeUnknownFields(ctx)
+	if len(fields) == 0 {
+		return
+	}
+	diags.AddWarning(
+		"Unknown fields in Terrareg response",
+		fmt.Sprintf(
+			"Terrareg returned fields that are not supported by this version of the provider, which have been ignored:\n\n  - %s\n\n"+
+				"This generally means that Terrareg is newer than the provider. Upgrade the provider, if a newer version is available. "+
+				"Set strict_api_decoding in the provider configuration to treat unknown fields as errors.",
+			strings.Join(fields, "\n  - "),
+		),
+	)
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/dockstudios/terraform-provider-terrareg/internal/terrareg"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -85,6 +85,9 @@ func (d *GitProviderDataSource) Configure(ctx context.Context, req datasource.Co
 }
 
 func (d *GitProviderDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, d.client)
+	defer done()
+
 	var data GitProviderDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -99,9 +102,9 @@ func (d *GitProviderDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	gitProviders, err := d.client.GetGitProviders()
+	gitProviders, err := d.client.GetGitProviders(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read git providers", err)
 		return
 	}
 
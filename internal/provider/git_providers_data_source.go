@@ -8,7 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/dockstudios/terraform-provider-terrareg/internal/terrareg"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -77,6 +77,9 @@ func (d *GitProvidersDataSource) Configure(ctx context.Context, req datasource.C
 }
 
 func (d *GitProvidersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, d.client)
+	defer done()
+
 	var data GitProvidersDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -86,9 +89,9 @@ func (d *GitProvidersDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	gitProviders, err := d.client.GetGitProviders()
+	gitProviders, err := d.client.GetGitProviders(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read git providers", err)
 		return
 	}
 
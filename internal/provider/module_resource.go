@@ -2,16 +2,17 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/dockstudios/terraform-provider-terrareg/internal/terrareg"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -40,6 +41,7 @@ type ModuleResourceModel struct {
 	RepoBrowseUrlTemplate types.String `tfsdk:"repo_browse_url_template"`
 	GitTagFormat          types.String `tfsdk:"git_tag_format"`
 	GitPath               types.String `tfsdk:"git_path"`
+	Source                types.String `tfsdk:"source"`
 }
 
 func (r *ModuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -62,14 +64,23 @@ func (r *ModuleResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"namespace": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Namespace of the module",
+				Validators: []validator.String{
+					namespaceNameValidator(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Module name",
+				Validators: []validator.String{
+					moduleNameValidator(),
+				},
 			},
 			"provider_name": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Module provider",
+				Validators: []validator.String{
+					moduleProviderNameValidator(),
+				},
 			},
 			"git_provider_id": schema.Int64Attribute{
 				Optional: true,
@@ -116,6 +127,10 @@ Note that if the {version} placeholder is not used, the module version import AP
 				Optional:            true,
 				MarkdownDescription: "Set the path within the repository that the module exists. Defaults to the root of the repository.",
 			},
+			"source": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Source address for using the module in a Terraform `module` block (e.g. `terrareg.example.com/namespace/name/provider`). The host is obtained from the provider `url` or service discovery.",
+			},
 		},
 	}
 }
@@ -141,6 +156,9 @@ func (r *ModuleResource) Configure(ctx context.Context, req resource.ConfigureRe
 }
 
 func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var data ModuleResourceModel
 
 	// Read Terraform plan data into the model
@@ -151,9 +169,8 @@ func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	id, err := r.client.CreateModule(
-		data.Namespace.ValueString(),
-		data.Name.ValueString(),
-		data.Provider.ValueString(),
+		ctx,
+		data.moduleProviderId(),
 		terrareg.ModuleModel{
 			GitProviderID:         data.GitProviderID.ValueInt64(),
 			RepoBaseUrlTemplate:   data.RepoBaseUrlTemplate.ValueString(),
@@ -164,22 +181,32 @@ func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest,
 		},
 	)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create module, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create module", err)
 		return
 	}
 
 	// Set ID attribute
-	data.ID = types.StringValue(id)
+	data.ID = types.StringValue(id.String())
+	data.Source = types.StringValue(r.client.ModuleSource(id))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *ModuleResource) generateId(namespace string, name string, provider string) string {
-	return fmt.Sprintf("%s/%s/%s", namespace, name, provider)
+// moduleProviderId returns the ID of the module provider
+// from the namespace, name and provider attributes
+func (m ModuleResourceModel) moduleProviderId() terrareg.ModuleProviderID {
+	return terrareg.ModuleProviderID{
+		Namespace: m.Namespace.ValueString(),
+		Name:      m.Name.ValueString(),
+		Provider:  m.Provider.ValueString(),
+	}
 }
 
 func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var data ModuleResourceModel
 
 	// Read Terraform prior state data into the model
@@ -190,7 +217,7 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	// Use existing ID, if state is not available for namespace, name or provider
-	var namespace, name, provider string
+	var id terrareg.ModuleProviderID
 	if data.Namespace.IsUnknown() ||
 		data.Namespace.IsNull() ||
 		data.Name.IsUnknown() ||
@@ -198,37 +225,35 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 		data.Provider.IsUnknown() ||
 		data.Provider.IsNull() {
 
-		splitId := strings.Split(data.ID.ValueString(), "/")
-		if len(splitId) != 3 {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("ID is an invalid format: %s", data.ID.ValueString()))
+		var err error
+		id, err = terrareg.ParseModuleProviderID(data.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("id"), "Invalid Module ID", err.Error())
 			return
 		}
-		namespace, name, provider = splitId[0], splitId[1], splitId[2]
 	} else {
-		namespace = data.Namespace.ValueString()
-		name = data.Name.ValueString()
-		provider = data.Provider.ValueString()
+		id = data.moduleProviderId()
 	}
 
-	module, err := r.client.GetModule(namespace, name, provider)
+	module, err := r.client.GetModule(ctx, id)
 	// If module was not found, set ID to empty value
-	if err == terrareg.ErrNotFound {
+	if errors.Is(err, terrareg.ErrNotFound) {
 		resp.State.RemoveResource(ctx)
 		return
 	} else if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read module, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read module", err)
 		return
 	}
 
 	// Update attributes, if they've modified
-	if data.Namespace.ValueString() != namespace {
-		data.Namespace = types.StringValue(namespace)
+	if data.Namespace.ValueString() != id.Namespace {
+		data.Namespace = types.StringValue(id.Namespace)
 	}
-	if data.Name.ValueString() != name {
-		data.Name = types.StringValue(name)
+	if data.Name.ValueString() != id.Name {
+		data.Name = types.StringValue(id.Name)
 	}
-	if data.Provider.ValueString() != provider {
-		data.Provider = types.StringValue(provider)
+	if data.Provider.ValueString() != id.Provider {
+		data.Provider = types.StringValue(id.Provider)
 	}
 	if data.GitProviderID.ValueInt64() != module.GitProviderID {
 		data.GitProviderID = types.Int64Value(module.GitProviderID)
@@ -248,12 +273,16 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if data.GitPath.ValueString() != module.GitPath {
 		data.GitPath = types.StringValue(module.GitPath)
 	}
+	data.Source = types.StringValue(r.client.ModuleSource(id))
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var plan ModuleResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	var state ModuleResourceModel
@@ -277,10 +306,9 @@ func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest,
 		newProvider = plan.Provider.ValueString()
 	}
 
-	_, err := r.client.UpdateModule(
-		state.Namespace.ValueString(),
-		state.Name.ValueString(),
-		state.Provider.ValueString(),
+	id, err := r.client.UpdateModule(
+		ctx,
+		state.moduleProviderId(),
 		terrareg.ModuleUpdateModel{
 			Namespace: newNamespace,
 			Name:      newName,
@@ -296,20 +324,24 @@ func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest,
 		},
 	)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update module, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update module", err)
 		return
 	}
 
-	newId := types.StringValue(r.generateId(plan.Namespace.ValueString(), plan.Name.ValueString(), plan.Provider.ValueString()))
+	newId := types.StringValue(id.String())
 	if !plan.ID.Equal(newId) {
 		plan.ID = newId
 	}
+	plan.Source = types.StringValue(r.client.ModuleSource(id))
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 func (r *ModuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var state ModuleResourceModel
 
 	// Read Terraform prior state data into the model
@@ -319,14 +351,26 @@ func (r *ModuleResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.DeleteModule(state.Namespace.ValueString(), state.Name.ValueString(), state.Provider.ValueString())
+	err := r.client.DeleteModule(ctx, state.moduleProviderId())
+	// If the module has already been deleted outside of Terraform,
+	// there is nothing left to do
+	if errors.Is(err, terrareg.ErrNotFound) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete module, got error: %s", err))
+		addClientError(&resp.Diagnostics, "delete module", err)
 		return
 	}
 }
 
 func (r *ModuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, err := terrareg.ParseModuleProviderID(req.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Modules are imported using an ID in the form namespace/name/provider: %s", err),
+		)
+		return
+	}
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
@@ -339,13 +383,46 @@ func (r ModuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanR
 		return
 	}
 
+	// Ensure custom git URLs are allowed by Terrareg, if they've been set,
+	// unless the Terrareg configuration does not report whether they are allowed
+	if r.client != nil && r.client.Capabilities() != nil && r.client.Capabilities().Config != nil &&
+		r.client.Capabilities().Config.AllowCustomGitUrlModuleProvider != nil &&
+		!*r.client.Capabilities().Config.AllowCustomGitUrlModuleProvider {
+		for _, attribute := range []struct {
+			name  string
+			value types.String
+		}{
+			{"repo_base_url_template", plan.RepoBaseUrlTemplate},
+			{"repo_clone_url_template", plan.RepoCloneUrlTemplate},
+			{"repo_browse_url_template", plan.RepoBrowseUrlTemplate},
+		} {
+			if attribute.value.ValueString() != "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute.name),
+					"Custom git URLs not allowed",
+					"Terrareg has not been configured to allow custom git URLs for modules (ALLOW_CUSTOM_GIT_URL_MODULE_PROVIDER). "+
+						"Use a git provider instead, by setting git_provider_id.",
+				)
+			}
+		}
+	}
+
 	if !plan.Namespace.IsNull() && !plan.Namespace.IsUnknown() && !plan.Name.IsNull() && !plan.Name.IsUnknown() && !plan.Provider.IsNull() && !plan.Provider.IsUnknown() {
-		newId := r.generateId(plan.Namespace.ValueString(), plan.Name.ValueString(), plan.Provider.ValueString())
+		id := plan.moduleProviderId()
+		newId := id.String()
 
 		// If plan value of ID is not unknown and needs to be modified,
 		// update it.
 		if !plan.ID.IsUnknown() && plan.ID.ValueString() != newId {
 			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringValue(newId))...)
 		}
+
+		// Source address can be determined from the provider configuration
+		if r.client != nil {
+			source := r.client.ModuleSource(id)
+			if plan.Source.ValueString() != source {
+				resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source"), types.StringValue(source))...)
+			}
+		}
 	}
 }
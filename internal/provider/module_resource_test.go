@@ -1,9 +1,11 @@
 package provider
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 func TestAccModuleResource_basic(t *testing.T) {
@@ -19,6 +21,7 @@ func TestAccModuleResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("terrareg_module.example", "namespace", "module-basic-example"),
 					resource.TestCheckResourceAttr("terrareg_module.example", "name", "basic-example"),
 					resource.TestCheckResourceAttr("terrareg_module.example", "provider_name", "aws"),
+					resource.TestCheckResourceAttr("terrareg_module.example", "source", testAccClient(t).ModuleSource(terrareg.ModuleProviderID{Namespace: "module-basic-example", Name: "basic-example", Provider: "aws"})),
 				),
 			},
 			// ImportState testing
@@ -96,6 +99,45 @@ func TestAccModuleResource_full(t *testing.T) {
 	})
 }
 
+func TestAccModuleResource_deleted_outside_terraform(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildTestProviderConfig(testAccModuleResourceConfig_deleted),
+			},
+			// Delete module outside of Terraform and ensure
+			// it is planned to be re-created
+			{
+				PreConfig: func() {
+					err := testAccClient(t).DeleteModule(context.Background(), terrareg.ModuleProviderID{Namespace: "module-deleted-example", Name: "deleted", Provider: "aws"})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             buildTestProviderConfig(testAccModuleResourceConfig_deleted),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+const testAccModuleResourceConfig_deleted = `
+resource "terrareg_namespace" "this" {
+  name = "module-deleted-example"
+}
+
+resource "terrareg_module" "example" {
+  namespace      = terrareg_namespace.this.name
+  name           = "deleted"
+  provider_name  = "aws"
+
+  git_tag_format  = "v{version}"
+}
+`
+
 const testAccNamespaceResourceConfig_basic = `
 resource "terrareg_namespace" "this" {
   name = "module-basic-example"
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NamespaceDataSource{}
+
+func NewNamespaceDataSource() datasource.DataSource {
+	return &NamespaceDataSource{}
+}
+
+// NamespaceDataSource defines the data source implementation.
+type NamespaceDataSource struct {
+	client *terrareg.TerraregClient
+}
+
+// NamespaceDataSourceModel describes the data source data model.
+type NamespaceDataSourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	DisplayName    types.String `tfsdk:"display_name"`
+	Trusted        types.Bool   `tfsdk:"trusted"`
+	IsAutoVerified types.Bool   `tfsdk:"is_auto_verified"`
+	ModuleCount    types.Int64  `tfsdk:"module_count"`
+}
+
+func (d *NamespaceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_namespace"
+}
+
+func (d *NamespaceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Data source for obtaining the details of a namespace",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Internal ID",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Namespace name",
+				Validators: []validator.String{
+					namespaceNameValidator(),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "User-friendly Namespace display name",
+			},
+			"trusted": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the namespace is trusted",
+			},
+			"is_auto_verified": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether modules in the namespace are automatically verified",
+			},
+			"module_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of modules (module providers) in the namespace, including modules without published versions",
+			},
+		},
+	}
+}
+
+func (d *NamespaceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*terrareg.TerraregClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *terrareg.TerraregClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NamespaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, d.client)
+	defer done()
+
+	var data NamespaceDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, err := d.client.GetNamespace(ctx, data.Name.ValueString())
+	if errors.Is(err, terrareg.ErrNotFound) {
+		resp.Diagnostics.AddError("Namespace Not Found", fmt.Sprintf("Namespace %q does not exist.", data.Name.ValueString()))
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read namespace", err)
+		return
+	}
+
+	modules, err := d.client.ListNamespaceModules(ctx, data.Name.ValueString())
+	// Terrareg may respond that the listing is not found for namespaces
+	// without modules, which has been confirmed to exist above
+	if errors.Is(err, terrareg.ErrNotFound) {
+		modules, err = nil, nil
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list namespace modules", err)
+		return
+	}
+
+	data.ID = data.Name
+	data.DisplayName = types.StringValue(namespace.DisplayName)
+	data.Trusted = types.BoolValue(namespace.Trusted)
+	data.IsAutoVerified = types.BoolValue(namespace.IsAutoVerified)
+	data.ModuleCount = types.Int64Value(int64(len(modules)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
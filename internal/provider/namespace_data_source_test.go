@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/dockstudios/terraform-provider-terrareg/internal/terraregtest"
+)
+
+func TestAccNamespaceDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: buildTestProviderConfig(testAccNamespaceDataSourceConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "id", "namespace-data-source"),
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "display_name", "Data Source"),
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "trusted", "false"),
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "is_auto_verified", "false"),
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "module_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceDataSource_trust(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildTestProviderConfig(testAccNamespaceDataSourceConfig),
+			},
+			// Trust is configured in Terrareg, rather than using the API
+			{
+				PreConfig: func() {
+					if !server.SetNamespaceTrust("namespace-data-source", false, true) {
+						t.Fatal("namespace does not exist")
+					}
+				},
+				Config: buildTestProviderConfig(testAccNamespaceDataSourceConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "trusted", "true"),
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "is_auto_verified", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceDataSource_empty(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildTestProviderConfig(testAccNamespaceDataSourceEmptyConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "id", "namespace-data-source-empty"),
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "module_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceDataSource_modules_not_found(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Listing the modules of an empty namespace is not found
+			{
+				PreConfig: func() {
+					server.InjectFault(terraregtest.Fault{
+						Method:     http.MethodGet,
+						Path:       "/v1/terrareg/modules/namespace-data-source-empty",
+						StatusCode: http.StatusNotFound,
+					})
+				},
+				Config: buildTestProviderConfig(testAccNamespaceDataSourceEmptyConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_namespace.this", "module_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceDataSource_not_found(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildTestProviderConfig(`
+data "terrareg_namespace" "this" {
+  name = "namespace-does-not-exist"
+}
+`),
+				ExpectError: regexp.MustCompile(`Namespace "namespace-does-not-exist" does not exist`),
+			},
+		},
+	})
+}
+
+const testAccNamespaceDataSourceConfig = `
+resource "terrareg_namespace" "this" {
+  name         = "namespace-data-source"
+  display_name = "Data Source"
+}
+
+resource "terrareg_module" "this" {
+  namespace     = terrareg_namespace.this.name
+  name          = "example"
+  provider_name = "aws"
+
+  git_tag_format = "v{version}"
+}
+
+data "terrareg_namespace" "this" {
+  name = terrareg_namespace.this.name
+
+  depends_on = [terrareg_module.this]
+}
+`
+
+const testAccNamespaceDataSourceEmptyConfig = `
+resource "terrareg_namespace" "this" {
+  name = "namespace-data-source-empty"
+}
+
+data "terrareg_namespace" "this" {
+  name = terrareg_namespace.this.name
+}
+`
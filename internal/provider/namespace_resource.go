@@ -2,18 +2,27 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/dockstudios/terraform-provider-terrareg/internal/terrareg"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &NamespaceResource{}
 var _ resource.ResourceWithImportState = &NamespaceResource{}
+var _ resource.ResourceWithModifyPlan = &NamespaceResource{}
 
 func NewNamespaceResource() resource.Resource {
 	return &NamespaceResource{}
@@ -26,9 +35,37 @@ type NamespaceResource struct {
 
 // NamespaceResourceModel describes the resource data model.
 type NamespaceResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	DisplayName types.String `tfsdk:"display_name"`
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	DisplayName    types.String `tfsdk:"display_name"`
+	Trusted        types.Bool   `tfsdk:"trusted"`
+	IsAutoVerified types.Bool   `tfsdk:"is_auto_verified"`
+	ForceDestroy   types.Bool   `tfsdk:"force_destroy"`
+}
+
+// configModel returns the settings of the namespace to send to Terrareg.
+func (m NamespaceResourceModel) configModel() terrareg.NamespaceConfigModel {
+	return terrareg.NamespaceConfigModel{
+		Name:        m.Name.ValueString(),
+		DisplayName: m.DisplayName.ValueString(),
+	}
+}
+
+// setTrust updates the trust attributes from the namespace in Terrareg.
+func (m *NamespaceResourceModel) setTrust(namespace *terrareg.NamespaceModel) {
+	m.Trusted = types.BoolValue(namespace.Trusted)
+	m.IsAutoVerified = types.BoolValue(namespace.IsAutoVerified)
+}
+
+// clearUnknownTrust sets trust attributes that are not yet known to null,
+// so that the namespace can be saved to state before its trust is read.
+func (m *NamespaceResourceModel) clearUnknownTrust() {
+	if m.Trusted.IsUnknown() {
+		m.Trusted = types.BoolNull()
+	}
+	if m.IsAutoVerified.IsUnknown() {
+		m.IsAutoVerified = types.BoolNull()
+	}
 }
 
 func (r *NamespaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -47,11 +84,35 @@ func (r *NamespaceResource) Schema(ctx context.Context, req resource.SchemaReque
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Namespace name",
 				Required:            true,
+				Validators: []validator.String{
+					namespaceNameValidator(),
+				},
 			},
 			"display_name": schema.StringAttribute{
 				MarkdownDescription: "User-friendly Namespace display name",
 				Optional:            true,
 			},
+			"trusted": schema.BoolAttribute{
+				MarkdownDescription: "Whether the namespace is trusted. This is configured in Terrareg (`TRUSTED_NAMESPACES`), rather than by the provider.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"is_auto_verified": schema.BoolAttribute{
+				MarkdownDescription: "Whether modules in the namespace are automatically verified. This is configured in Terrareg (`VERIFIED_MODULE_NAMESPACES`), rather than by the provider.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Delete all modules in the namespace, including all of their versions, when destroying the namespace. " +
+					"Otherwise, destroying a namespace that contains modules fails. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 		},
 	}
 }
@@ -77,6 +138,9 @@ func (r *NamespaceResource) Configure(ctx context.Context, req resource.Configur
 }
 
 func (r *NamespaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var data NamespaceResourceModel
 
 	// Read Terraform plan data into the model
@@ -86,23 +150,39 @@ func (r *NamespaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	err := r.client.CreateNamespace(terrareg.NamespaceConfigModel{
-		Name:        data.Name.ValueString(),
-		DisplayName: data.DisplayName.ValueString(),
-	})
+	err := r.client.CreateNamespace(ctx, data.configModel())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create namespace, got error: %s", err))
+		addClientError(&resp.Diagnostics, "create namespace", err)
 		return
 	}
 
 	// Set ID attribute
 	data.ID = data.Name
 
+	// Save the namespace into Terraform state before obtaining its trust,
+	// so that the namespace is tracked even if reading it fails
+	data.clearUnknownTrust()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain the trust of the namespace, which is determined by Terrareg
+	namespace, err := r.client.GetNamespace(ctx, data.Name.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read namespace", err)
+		return
+	}
+	data.setTrust(namespace)
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *NamespaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var data NamespaceResourceModel
 
 	// Read Terraform prior state data into the model
@@ -117,14 +197,14 @@ func (r *NamespaceResource) Read(ctx context.Context, req resource.ReadRequest,
 		data.ID = data.Name
 	}
 
-	namespace, err := r.client.GetNamespace(data.ID.ValueString())
+	namespace, err := r.client.GetNamespace(ctx, data.ID.ValueString())
 	// If namespace was not found, set Name to empty value
-	if err == terrareg.ErrNotFound {
+	if errors.Is(err, terrareg.ErrNotFound) {
 		resp.State.RemoveResource(ctx)
 		return
 	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read namespace, got error: %s", err))
+		addClientError(&resp.Diagnostics, "read namespace", err)
 		return
 	}
 
@@ -132,12 +212,21 @@ func (r *NamespaceResource) Read(ctx context.Context, req resource.ReadRequest,
 	if data.DisplayName.ValueString() != namespace.DisplayName {
 		data.DisplayName = types.StringValue(namespace.DisplayName)
 	}
+	data.setTrust(namespace)
+
+	// force_destroy is not stored in Terrareg, so is unset after import
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var data NamespaceResourceModel
 
 	// Read Terraform plan data into the model
@@ -147,20 +236,28 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	// Get old namespace name
-	var name types.String
-	diags := req.State.GetAttribute(ctx, path.Root("name"), &name)
-	resp.Diagnostics.Append(diags...)
+	var state NamespaceResourceModel
 
-	err := r.client.UpdateNamespace(
-		name.ValueString(),
-		terrareg.NamespaceConfigModel{
-			Name:        data.Name.ValueString(),
-			DisplayName: data.DisplayName.ValueString(),
-		},
-	)
+	// Read Terraform prior state data, for the old namespace name
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// force_destroy is not stored in Terrareg, so there is nothing
+	// to update if no other attributes have changed
+	if data.Name.Equal(state.Name) && data.DisplayName.Equal(state.DisplayName) {
+		data.ID = state.ID
+		data.Trusted = state.Trusted
+		data.IsAutoVerified = state.IsAutoVerified
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	err := r.client.UpdateNamespace(ctx, state.Name.ValueString(), data.configModel())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update namespace, got error: %s", err))
+		addClientError(&resp.Diagnostics, "update namespace", err)
 		return
 	}
 
@@ -168,11 +265,30 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 		data.ID = data.Name
 	}
 
+	// Save the (possibly renamed) namespace into Terraform state before
+	// obtaining its trust, so that the new name is tracked even if reading it fails
+	data.clearUnknownTrust()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Trust may change when renaming the namespace
+	namespace, err := r.client.GetNamespace(ctx, data.Name.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "read namespace", err)
+		return
+	}
+	data.setTrust(namespace)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *NamespaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, r.client)
+	defer done()
+
 	var data NamespaceResourceModel
 
 	// Read Terraform prior state data into the model
@@ -182,13 +298,100 @@ func (r *NamespaceResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	err := r.client.DeleteNamespace(data.Name.ValueString())
+	// Terrareg refuses to delete namespaces that contain modules,
+	// so either delete the modules or report the modules to the user
+	modules, err := r.client.ListNamespaceModules(ctx, data.Name.ValueString())
+	// Terrareg does not distinguish between a namespace that does not exist
+	// and a listing that is not found, so attempt to delete the namespace
+	// regardless, which is ignored if it has already been deleted
+	if errors.Is(err, terrareg.ErrNotFound) {
+		modules, err = nil, nil
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list namespace modules", err)
+		return
+	}
+	if len(modules) > 0 {
+		if !data.ForceDestroy.ValueBool() {
+			moduleIds := make([]string, 0, len(modules))
+			for _, id := range modules {
+				moduleIds = append(moduleIds, id.String())
+			}
+			resp.Diagnostics.AddError(
+				"Namespace Contains Modules",
+				fmt.Sprintf(
+					"Namespace %q cannot be deleted, as it contains %d module(s):\n\n  - %s\n\n"+
+						"Delete the modules first, or set force_destroy = true to delete the modules along with the namespace.",
+					data.Name.ValueString(), len(modules), strings.Join(moduleIds, "\n  - "),
+				),
+			)
+			return
+		}
+		if !r.deleteModules(ctx, &resp.Diagnostics, modules) {
+			return
+		}
+	}
+
+	err = r.client.DeleteNamespace(ctx, data.Name.ValueString())
+	// If the namespace has already been deleted outside of Terraform,
+	// there is nothing left to do
+	if errors.Is(err, terrareg.ErrNotFound) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete namespace, got error: %s", err))
+		addClientError(&resp.Diagnostics, "delete namespace", err)
 		return
 	}
 }
 
+// deleteModules deletes all modules in a namespace, for force_destroy,
+// returning false if any module could not be deleted.
+func (r *NamespaceResource) deleteModules(ctx context.Context, diags *diag.Diagnostics, modules []terrareg.ModuleProviderID) bool {
+	tflog.Info(ctx, "Deleting modules in namespace before destroying namespace", map[string]interface{}{
+		"namespace":    modules[0].Namespace,
+		"module_count": len(modules),
+	})
+	for i, id := range modules {
+		tflog.Info(ctx, "Deleting module", map[string]interface{}{
+			"module":   id.String(),
+			"progress": fmt.Sprintf("%d/%d", i+1, len(modules)),
+		})
+		err := r.client.DeleteModule(ctx, id)
+		// Ignore modules that have been deleted since they were listed
+		if errors.Is(err, terrareg.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			addClientError(diags, fmt.Sprintf("delete module %s (deleted %d of %d modules)", id, i, len(modules)), err)
+			return false
+		}
+	}
+	tflog.Info(ctx, "Deleted all modules in namespace", map[string]interface{}{
+		"namespace":    modules[0].Namespace,
+		"module_count": len(modules),
+	})
+	return true
+}
+
 func (r *NamespaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
+
+func (r *NamespaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	// Trust is configured in Terrareg by namespace name,
+	// so is not known until after renaming the namespace
+	var plan, state NamespaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.Name.Equal(state.Name) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("trusted"), types.BoolUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("is_auto_verified"), types.BoolUnknown())...)
+	}
+}
@@ -1,10 +1,17 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/dockstudios/terraform-provider-terrareg/internal/terraregtest"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 func TestAccNamespaceResource(t *testing.T) {
@@ -44,6 +51,268 @@ func TestAccNamespaceResource(t *testing.T) {
 	})
 }
 
+func TestAccNamespaceResource_trust(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNamespaceResourceConfig("trust", "Trust"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "trusted", "false"),
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "is_auto_verified", "false"),
+				),
+			},
+			// Trust configured in Terrareg is obtained when refreshing
+			{
+				PreConfig: func() {
+					if !server.SetNamespaceTrust("trust", true, true) {
+						t.Fatal("namespace does not exist")
+					}
+				},
+				Config: testAccNamespaceResourceConfig("trust", "Trust"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "trusted", "true"),
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "is_auto_verified", "true"),
+				),
+			},
+			// Trust is obtained again after updating the namespace
+			{
+				Config: testAccNamespaceResourceConfig("trust", "Trust Updated"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "trusted", "true"),
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "is_auto_verified", "true"),
+				),
+			},
+			// Trust must not be set in the configuration
+			{
+				Config: buildTestProviderConfig(`
+resource "terrareg_namespace" "test" {
+  name    = "trust"
+  trusted = true
+}
+`),
+				ExpectError: regexp.MustCompile(`Invalid Configuration for Read-Only Attribute`),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceResource_deleted_outside_terraform(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNamespaceResourceConfig("deleted-outside-terraform", "Deleted"),
+			},
+			// Delete namespace outside of Terraform and ensure
+			// it is planned to be re-created
+			{
+				PreConfig: func() {
+					err := testAccClient(t).DeleteNamespace(context.Background(), "deleted-outside-terraform")
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccNamespaceResourceConfig("deleted-outside-terraform", "Deleted"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccNamespaceResource_server_errors(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Permission errors are returned to the user
+			{
+				PreConfig: func() {
+					server.InjectFault(terraregtest.Fault{
+						Method:     http.MethodPost,
+						Path:       "/v1/terrareg/namespaces",
+						StatusCode: http.StatusForbidden,
+						Times:      1,
+					})
+				},
+				Config:      testAccNamespaceResourceConfig("server-errors", "Server Errors"),
+				ExpectError: regexp.MustCompile(`Unauthorized`),
+			},
+			// Transient server errors are retried
+			{
+				PreConfig: func() {
+					server.InjectFault(terraregtest.Fault{
+						Method:     http.MethodPost,
+						Path:       "/v1/terrareg/namespaces",
+						StatusCode: http.StatusServiceUnavailable,
+						Times:      1,
+					})
+				},
+				Config: testAccNamespaceResourceConfig("server-errors", "Server Errors"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "name", "server-errors"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceResource_force_destroy(t *testing.T) {
+	moduleIds := []terrareg.ModuleProviderID{
+		{Namespace: "force-destroy", Name: "one", Provider: "aws"},
+		{Namespace: "force-destroy", Name: "two", Provider: "gcp"},
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			_, err := testAccClient(t).GetNamespace(context.Background(), "force-destroy")
+			if !errors.Is(err, terrareg.ErrNotFound) {
+				return fmt.Errorf("expected namespace to be destroyed, got: %v", err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNamespaceResourceConfig_force_destroy("force-destroy", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "force_destroy", "true"),
+				),
+			},
+			// Create modules outside of Terraform, which are
+			// deleted along with the namespace
+			{
+				PreConfig: func() {
+					for _, id := range moduleIds {
+						_, err := testAccClient(t).CreateModule(context.Background(), id, terrareg.ModuleModel{GitTagFormat: "v{version}"})
+						if err != nil {
+							t.Fatal(err)
+						}
+					}
+				},
+				Config: testAccNamespaceResourceConfig_force_destroy("force-destroy", true),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceResource_destroy_containing_modules(t *testing.T) {
+	moduleId := terrareg.ModuleProviderID{Namespace: "destroy-containing-modules", Name: "blocking", Provider: "aws"}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNamespaceResourceConfig_force_destroy("destroy-containing-modules", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "force_destroy", "false"),
+				),
+			},
+			// Destroying the namespace reports the modules that block deletion
+			{
+				PreConfig: func() {
+					_, err := testAccClient(t).CreateModule(context.Background(), moduleId, terrareg.ModuleModel{GitTagFormat: "v{version}"})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccNamespaceResourceConfig_force_destroy("destroy-containing-modules", false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`(?s)Namespace Contains Modules.*destroy-containing-modules/blocking/aws`),
+			},
+			// Remove the module, so that the namespace can be destroyed
+			{
+				PreConfig: func() {
+					if err := testAccClient(t).DeleteModule(context.Background(), moduleId); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccNamespaceResourceConfig_force_destroy("destroy-containing-modules", false),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceResource_force_destroy_not_updated(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNamespaceResourceConfig_force_destroy("force-destroy-not-updated", false),
+			},
+			// force_destroy is not stored in Terrareg,
+			// so changing it does not update the namespace
+			{
+				PreConfig: func() {
+					server.InjectFault(terraregtest.Fault{
+						Method:     http.MethodPost,
+						Path:       "/v1/terrareg/namespaces/force-destroy-not-updated",
+						StatusCode: http.StatusInternalServerError,
+					})
+				},
+				Config: testAccNamespaceResourceConfig_force_destroy("force-destroy-not-updated", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("terrareg_namespace.test", "force_destroy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceResource_destroy_modules_not_found(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			_, err := testAccClient(t).GetNamespace(context.Background(), "destroy-modules-not-found")
+			if !errors.Is(err, terrareg.ErrNotFound) {
+				return fmt.Errorf("expected namespace to be destroyed, got: %v", err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNamespaceResourceConfig_force_destroy("destroy-modules-not-found", false),
+			},
+			// The namespace is still deleted if listing its modules is not found
+			{
+				PreConfig: func() {
+					server.InjectFault(terraregtest.Fault{
+						Method:     http.MethodGet,
+						Path:       "/v1/terrareg/modules/destroy-modules-not-found",
+						StatusCode: http.StatusNotFound,
+					})
+				},
+				Config:  testAccNamespaceResourceConfig_force_destroy("destroy-modules-not-found", false),
+				Destroy: true,
+			},
+		},
+	})
+}
+
+func testAccNamespaceResourceConfig_force_destroy(name string, forceDestroy bool) string {
+	return buildTestProviderConfig(fmt.Sprintf(`
+resource "terrareg_namespace" "test" {
+  name          = %[1]q
+  force_destroy = %[2]t
+}
+`, name, forceDestroy))
+}
+
 func testAccNamespaceResourceConfig(name string, displayName string) string {
 	return buildTestProviderConfig(fmt.Sprintf(`
 resource "terrareg_namespace" "test" {
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NamespacesDataSource{}
+
+func NewNamespacesDataSource() datasource.DataSource {
+	return &NamespacesDataSource{}
+}
+
+// NamespacesDataSource defines the data source implementation.
+type NamespacesDataSource struct {
+	client *terrareg.TerraregClient
+}
+
+// NamespacesDataSourceModel describes the data source data model.
+type NamespacesDataSourceModel struct {
+	Id          types.String                    `tfsdk:"id"`
+	NameRegex   types.String                    `tfsdk:"name_regex"`
+	TrustedOnly types.Bool                      `tfsdk:"trusted_only"`
+	Namespaces  []NamespacesDataSourceNamespace `tfsdk:"namespaces"`
+}
+
+// NamespacesDataSourceNamespace describes a namespace in the data source.
+type NamespacesDataSourceNamespace struct {
+	Name           string `tfsdk:"name"`
+	DisplayName    string `tfsdk:"display_name"`
+	Trusted        bool   `tfsdk:"trusted"`
+	IsAutoVerified bool   `tfsdk:"is_auto_verified"`
+}
+
+func (d *NamespacesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_namespaces"
+}
+
+func (d *NamespacesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Data source for obtaining all namespaces, optionally filtered by name or trust.\n\n" +
+			"Terrareg does not include the trust of namespaces when listing them, so a request is made for each namespace matching `name_regex`. " +
+			"Set `name_regex` to limit the number of requests made for Terrareg instances with many namespaces.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Internal ID",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Regular expression (RE2 syntax) that namespace names must match",
+				Validators: []validator.String{
+					regexValidator{},
+				},
+			},
+			"trusted_only": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return trusted namespaces",
+			},
+			"namespaces": schema.ListAttribute{
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"name":             types.StringType,
+						"display_name":     types.StringType,
+						"trusted":          types.BoolType,
+						"is_auto_verified": types.BoolType,
+					},
+				},
+				MarkdownDescription: "List of namespaces, including name, display_name, trusted and is_auto_verified, sorted by name",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NamespacesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*terrareg.TerraregClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *terrareg.TerraregClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NamespacesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, d.client)
+	defer done()
+
+	var data NamespacesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+
+	summaries, err := d.client.ListNamespaces(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "list namespaces", err)
+		return
+	}
+
+	data.Namespaces = []NamespacesDataSourceNamespace{}
+	for _, summary := range summaries {
+		if nameRegex != nil && !nameRegex.MatchString(summary.Name) {
+			continue
+		}
+
+		// The trust of namespaces is not included when listing namespaces
+		namespace, err := d.client.GetNamespace(ctx, summary.Name)
+		// Ignore namespaces that have been deleted since they were listed
+		if errors.Is(err, terrareg.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			addClientError(&resp.Diagnostics, fmt.Sprintf("read namespace %q", summary.Name), err)
+			return
+		}
+		if data.TrustedOnly.ValueBool() && !namespace.Trusted {
+			continue
+		}
+
+		data.Namespaces = append(data.Namespaces, NamespacesDataSourceNamespace{
+			Name:           summary.Name,
+			DisplayName:    namespace.DisplayName,
+			Trusted:        namespace.Trusted,
+			IsAutoVerified: namespace.IsAutoVerified,
+		})
+	}
+	sort.Slice(data.Namespaces, func(i, j int) bool {
+		return data.Namespaces[i].Name < data.Namespaces[j].Name
+	})
+
+	// Create fake ID, required by Terraform
+	data.Id = types.StringValue("this")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/dockstudios/terraform-provider-terrareg/internal/terraregtest"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+func TestAccNamespacesDataSource(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildTestProviderConfig(testAccNamespacesDataSourceConfig),
+			},
+			// Read testing, with trust configured in Terrareg
+			{
+				PreConfig: func() {
+					if !server.SetNamespaceTrust("namespaces-ds-one", false, true) {
+						t.Fatal("namespace does not exist")
+					}
+				},
+				Config: buildTestProviderConfig(testAccNamespacesDataSourceConfig),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.filtered", "namespaces.#", "2"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.filtered", "namespaces.0.name", "namespaces-ds-one"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.filtered", "namespaces.0.display_name", "One"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.filtered", "namespaces.0.trusted", "true"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.filtered", "namespaces.1.name", "namespaces-ds-two"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.filtered", "namespaces.1.trusted", "false"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.trusted", "namespaces.#", "1"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.trusted", "namespaces.0.name", "namespaces-ds-one"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespacesDataSource_deleted_namespace(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Namespaces deleted between listing and reading them are omitted
+			{
+				PreConfig: func() {
+					// Create namespaces outside of Terraform,
+					// so that only the data source reads them
+					for _, name := range []string{"namespaces-ds-deleted-one", "namespaces-ds-deleted-two"} {
+						name := name
+						if err := testAccClient(t).CreateNamespace(context.Background(), terrareg.NamespaceConfigModel{Name: name}); err != nil {
+							t.Fatal(err)
+						}
+						t.Cleanup(func() {
+							if err := testAccClient(t).DeleteNamespace(context.Background(), name); err != nil {
+								t.Error(err)
+							}
+						})
+					}
+					server.InjectFault(terraregtest.Fault{
+						Method:     http.MethodGet,
+						Path:       "/v1/terrareg/namespaces/namespaces-ds-deleted-two",
+						StatusCode: http.StatusNotFound,
+					})
+				},
+				Config: buildTestProviderConfig(`
+data "terrareg_namespaces" "this" {
+  name_regex = "^namespaces-ds-deleted-"
+}
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.this", "namespaces.#", "1"),
+					resource.TestCheckResourceAttr("data.terrareg_namespaces.this", "namespaces.0.name", "namespaces-ds-deleted-one"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespacesDataSource_invalid_regex(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: buildTestProviderConfig(`
+data "terrareg_namespaces" "this" {
+  name_regex = "namespaces-("
+}
+`),
+				ExpectError: regexp.MustCompile(`Invalid Regular Expression`),
+			},
+		},
+	})
+}
+
+const testAccNamespacesDataSourceConfig = `
+resource "terrareg_namespace" "one" {
+  name         = "namespaces-ds-one"
+  display_name = "One"
+}
+
+resource "terrareg_namespace" "two" {
+  name = "namespaces-ds-two"
+}
+
+data "terrareg_namespaces" "filtered" {
+  name_regex = "^namespaces-ds-"
+
+  depends_on = [terrareg_namespace.one, terrareg_namespace.two]
+}
+
+data "terrareg_namespaces" "trusted" {
+  name_regex   = "^namespaces-ds-"
+  trusted_only = true
+
+  depends_on = [terrareg_namespace.one, terrareg_namespace.two]
+}
+`
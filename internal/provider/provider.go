@@ -2,19 +2,32 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/dockstudios/terraform-provider-terrareg/internal/terrareg"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 var _ provider.Provider = &TerraregProvider{}
 
+// Valid characters of an HTTP header name (RFC 7230 token)
+var headerNameRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
 type TerraregProvider struct {
 	TerraregProviderModel
 	version string
@@ -22,8 +35,35 @@ type TerraregProvider struct {
 
 // TerraregProviderModel describes the provider data model.
 type TerraregProviderModel struct {
-	Url    types.String `tfsdk:"url"`
-	ApiKey types.String `tfsdk:"api_key"`
+	Url          types.String `tfsdk:"url"`
+	ApiKey       types.String `tfsdk:"api_key"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin types.Int64  `tfsdk:"retry_wait_min"`
+	RetryWaitMax types.Int64  `tfsdk:"retry_wait_max"`
+
+	MaxIdleConnections types.Int64 `tfsdk:"max_idle_connections"`
+	RequestTimeout     types.Int64 `tfsdk:"request_timeout"`
+
+	RateLimit             types.Float64 `tfsdk:"rate_limit"`
+	RateLimitBurst        types.Int64   `tfsdk:"rate_limit_burst"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	CacheTTL              types.Int64   `tfsdk:"cache_ttl"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyUrl           types.String `tfsdk:"proxy_url"`
+	Headers            types.Map    `tfsdk:"headers"`
+
+	AdminToken    types.String `tfsdk:"admin_token"`
+	UploadApiKey  types.String `tfsdk:"upload_api_key"`
+	PublishApiKey types.String `tfsdk:"publish_api_key"`
+
+	VerifyCredentials types.Bool `tfsdk:"verify_credentials"`
+	ServiceDiscovery  types.Bool `tfsdk:"service_discovery"`
+	StrictApiDecoding types.Bool `tfsdk:"strict_api_decoding"`
 }
 
 func (p *TerraregProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -35,12 +75,152 @@ func (p *TerraregProvider) Schema(ctx context.Context, req provider.SchemaReques
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"url": schema.StringAttribute{
-				MarkdownDescription: "Terrareg url (e.g. https://terrareg.example.com)",
-				Required:            true,
+				MarkdownDescription: "Terrareg url (e.g. https://terrareg.example.com). If Terrareg is hosted under a sub-path, include the path (e.g. https://tools.example.com/registry)." + envDescription(envUrl),
+				Optional:            true,
 			},
 			"api_key": schema.StringAttribute{
-				MarkdownDescription: "API Key for authenticating to Terrareg (currently supports admin auth token)",
+				MarkdownDescription: "API Key for authenticating to Terrareg (currently supports admin auth token). Alias of `admin_token`." + envDescription(envApiKey),
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("admin_token")),
+				},
+			},
+			"admin_token": schema.StringAttribute{
+				MarkdownDescription: "Admin authentication token, used for managing namespaces and modules." + envDescription(envAdminToken) +
+					" If no admin token is provided, credentials for the Terrareg host are obtained from the Terraform CLI configuration, " +
+					"using a `TF_TOKEN_<host>` environment variable or the credentials file created by `terraform login`.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"upload_api_key": schema.StringAttribute{
+				MarkdownDescription: "Upload API key, used for uploading and importing module versions. If not provided, the admin token is used." + envDescription(envUploadApiKey),
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"publish_api_key": schema.StringAttribute{
+				MarkdownDescription: "Publish API key, used for publishing module versions. If not provided, the admin token is used." + envDescription(envPublishApiKey),
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"verify_credentials": schema.BoolAttribute{
+				MarkdownDescription: "Verify the admin token (or Terraform CLI credentials) against Terrareg when the provider is configured, failing early if they are invalid. " +
+					"Upload and publish API keys cannot be verified, so only a warning is reported if neither is provided. Defaults to `false`." + envDescription(envVerifyCredentials),
+				Optional: true,
+			},
+			"service_discovery": schema.BoolAttribute{
+				MarkdownDescription: "Obtain the Terrareg API endpoints using Terraform service discovery (`/.well-known/terraform.json` on the Terrareg host), rather than deriving them from `url`. " +
+					"The Terrareg API is obtained from the `terrareg.v1` service or, if not present, is expected alongside the `modules.v1` API. Defaults to `false`." + envDescription(envServiceDiscovery),
+				Optional: true,
+			},
+			"strict_api_decoding": schema.BoolAttribute{
+				MarkdownDescription: "Fail if Terrareg returns fields that are not supported by the provider, rather than ignoring them with a warning. " +
+					"Intended for testing compatibility with new versions of Terrareg. Defaults to `false`." + envDescription(envStrictApiDecoding),
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of times a request is retried after a connection error or a transient server error (429, 502, 503, 504). Requests that are not idempotent (e.g. creating a module) are only retried when Terrareg has not processed the request. Set to `0` to disable retries. Defaults to `%d`.", terrareg.DefaultMaxRetries) + envDescription(envMaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Minimum time, in seconds, to wait before retrying a request. Defaults to `%d`.", int64(terrareg.DefaultRetryWaitMin.Seconds())) + envDescription(envRetryWaitMin),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_max": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum time, in seconds, to wait before retrying a request. A `Retry-After` header returned by Terrareg is honoured, up to this value. Defaults to `%d`.", int64(terrareg.DefaultRetryWaitMax.Seconds())) + envDescription(envRetryWaitMax),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"max_idle_connections": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of idle connections to Terrareg that are kept open for re-use. Defaults to `%d`.", terrareg.DefaultMaxIdleConns) + envDescription(envMaxIdleConnections),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"request_timeout": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Timeout, in seconds, for each request to Terrareg. Set to `0` to disable the timeout, although read requests shared between resources are still limited to `%d` seconds. Defaults to `%d`.", int64(terrareg.DefaultRequestTimeout.Seconds()), int64(terrareg.DefaultRequestTimeout.Seconds())) + envDescription(envRequestTimeout),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"rate_limit": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of requests per second made to Terrareg. Set to `0` to disable rate limiting. Defaults to `0`." + envDescription(envRateLimit),
 				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"rate_limit_burst": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests that can be made at once before `rate_limit` is applied. Defaults to `1`." + envDescription(envRateLimitBurst),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests to Terrareg that can be in-flight at once. Set to `0` for no limit. Defaults to `0`. " +
+					"Regardless of this setting, requests that modify a namespace, or the modules within it, are made one at a time." + envDescription(envMaxConcurrentRequests),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"cache_ttl": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Time, in seconds, that read-mostly listings (git providers, Terrareg configuration and namespace lists) are cached by the provider. "+
+					"Cached namespace lists are refreshed when the provider modifies a namespace. Set to `0` to disable caching. Defaults to `%d`.", int64(terrareg.DefaultCacheTTL.Seconds())) + envDescription(envCacheTTL),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificate(s) to trust, in addition to the system CAs, when connecting to Terrareg. Conflicts with `ca_cert_file`." + envDescription(envCACertPEM),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_file")),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file containing PEM-encoded CA certificate(s) to trust, in addition to the system CAs, when connecting to Terrareg. Conflicts with `ca_cert_pem`." + envDescription(envCACertFile),
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate for authenticating to Terrareg using mutual TLS. Requires `client_key`." + envDescription(envClientCert),
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of the client certificate. Requires `client_cert`." + envDescription(envClientKey),
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Disable verification of the Terrareg server TLS certificate. This should only be used for testing." + envDescription(envInsecureSkipVerify),
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of a proxy to use for requests to Terrareg (e.g. http://proxy.example.com:3128). If not set, the proxy is obtained from the `HTTPS_PROXY`/`HTTP_PROXY` environment variables." + envDescription(envProxyUrl),
+				Optional:            true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Additional static HTTP headers sent with all requests to Terrareg (e.g. for an authenticating reverse proxy). " +
+					"These cannot override the headers set by the provider, such as `User-Agent`, `X-Request-ID` or authentication headers.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(
+						stringvalidator.RegexMatches(headerNameRegex, "must be a valid HTTP header name"),
+					),
+				},
 			},
 		},
 	}
@@ -55,6 +235,11 @@ func (p *TerraregProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	data.applyEnvironment(&resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if data.Url.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("url"),
@@ -68,13 +253,13 @@ func (p *TerraregProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	url := data.Url.ValueString()
-	if url == "" {
+	terraregUrl := data.Url.ValueString()
+	if terraregUrl == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("url"),
 			"Missing Url.",
 			"The provider must be configured with a URL. "+
-				"Set the host value in the configuration. "+
+				"Set the url value in the configuration or use the "+envUrl+" environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -82,9 +267,130 @@ func (p *TerraregProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	terraregUrl, err := terrareg.NormalizeUrl(terraregUrl)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Invalid Url",
+			fmt.Sprintf("The provider URL must be the base URL of Terrareg, such as https://terrareg.example.com or https://tools.example.com/registry: %s", err),
+		)
+		return
+	}
+
+	opts := []terrareg.Option{
+		terrareg.WithRateLimit(data.RateLimit.ValueFloat64(), int(data.RateLimitBurst.ValueInt64())),
+		terrareg.WithMaxConcurrentRequests(int(data.MaxConcurrentRequests.ValueInt64())),
+		terrareg.WithStrictDecoding(data.StrictApiDecoding.ValueBool()),
+		terrareg.WithInsecureSkipVerify(data.InsecureSkipVerify.ValueBool()),
+		terrareg.WithProxyURL(data.ProxyUrl.ValueString()),
+		terrareg.WithUploadApiKey(data.UploadApiKey.ValueString()),
+		terrareg.WithPublishApiKey(data.PublishApiKey.ValueString()),
+	}
+	if !data.MaxRetries.IsNull() {
+		opts = append(opts, terrareg.WithMaxRetries(int(data.MaxRetries.ValueInt64())))
+	}
+	retryWaitMin := terrareg.DefaultRetryWaitMin
+	if !data.RetryWaitMin.IsNull() {
+		retryWaitMin = time.Duration(data.RetryWaitMin.ValueInt64()) * time.Second
+	}
+	retryWaitMax := terrareg.DefaultRetryWaitMax
+	if !data.RetryWaitMax.IsNull() {
+		retryWaitMax = time.Duration(data.RetryWaitMax.ValueInt64()) * time.Second
+	}
+	if retryWaitMin > retryWaitMax {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_wait_min"),
+			"Invalid retry configuration",
+			fmt.Sprintf("retry_wait_min (%s) must not be greater than retry_wait_max (%s).", retryWaitMin, retryWaitMax),
+		)
+		return
+	}
+	opts = append(opts, terrareg.WithRetryWait(retryWaitMin, retryWaitMax))
+	if !data.MaxIdleConnections.IsNull() {
+		opts = append(opts, terrareg.WithMaxIdleConns(int(data.MaxIdleConnections.ValueInt64())))
+	}
+	if !data.RequestTimeout.IsNull() {
+		opts = append(opts, terrareg.WithRequestTimeout(time.Duration(data.RequestTimeout.ValueInt64())*time.Second))
+	}
+	if !data.CacheTTL.IsNull() {
+		opts = append(opts, terrareg.WithCacheTTL(time.Duration(data.CacheTTL.ValueInt64())*time.Second))
+	}
+
+	caCertPEM := data.CACertPEM.ValueString()
+	if !data.CACertFile.IsNull() {
+		caCert, err := os.ReadFile(data.CACertFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Unable to read CA certificate file",
+				fmt.Sprintf("Unable to read CA certificate file %q: %s", data.CACertFile.ValueString(), err),
+			)
+			return
+		}
+		caCertPEM = string(caCert)
+	}
+	opts = append(opts, terrareg.WithCACertificate(caCertPEM))
+
+	if data.ClientCert.IsNull() != data.ClientKey.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Invalid client certificate configuration",
+			"Both client_cert and client_key must be set to use mutual TLS.",
+		)
+		return
+	}
+	opts = append(opts, terrareg.WithClientCertificate(data.ClientCert.ValueString(), data.ClientKey.ValueString()))
+
+	if data.Headers.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("headers"),
+			"Unknown headers",
+			"The provider cannot create the Terrareg API client as there is an unknown configuration value for headers. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+		return
+	}
+	var headers map[string]string
+	resp.Diagnostics.Append(data.Headers.ElementsAs(ctx, &headers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	opts = append(opts, terrareg.WithHeaders(headers))
+
+	// Identify the provider and Terraform versions in the Terrareg access logs
+	userAgent := fmt.Sprintf("%s/%s", terrareg.DefaultUserAgent, p.version)
+	if req.TerraformVersion != "" {
+		userAgent += fmt.Sprintf(" terraform/%s", req.TerraformVersion)
+	}
+	opts = append(opts, terrareg.WithUserAgent(userAgent))
+
+	adminToken := data.AdminToken.ValueString()
+	if adminToken == "" {
+		adminToken = data.ApiKey.ValueString()
+	}
+	opts = append(opts, terrareg.WithAdminToken(adminToken))
+
+	// Fallback to credentials from the Terraform CLI configuration
+	// for the Terrareg host, if an admin token has not been provided
+	if adminToken == "" {
+		parsedUrl, err := url.Parse(terraregUrl)
+		if err == nil {
+			token, err := terrareg.TerraformCLIToken(parsedUrl.Host)
+			if err != nil {
+				resp.Diagnostics.AddWarning(
+					"Unable to read Terraform CLI credentials",
+					fmt.Sprintf("Terraform CLI credentials for %s could not be read, continuing without authentication: %s", parsedUrl.Host, err),
+				)
+			} else if token != "" {
+				tflog.Debug(ctx, "Using Terraform CLI credentials for Terrareg host", map[string]interface{}{"host": parsedUrl.Host})
+				opts = append(opts, terrareg.WithBearerToken(token))
+			}
+		}
+	}
+
 	tflog.Debug(ctx, "Creating Terrareg client")
 
-	api, err := terrareg.NewClient(url, data.ApiKey.ValueString())
+	api, err := terrareg.NewClient(terraregUrl, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create Terrareg API Client",
@@ -94,6 +400,72 @@ func (p *TerraregProvider) Configure(ctx context.Context, req provider.Configure
 		)
 		return
 	}
+	ctx, done := collectUnknownFields(ctx, &resp.Diagnostics, api)
+	defer done()
+
+	if data.ServiceDiscovery.ValueBool() {
+		err = api.DiscoverServices(ctx)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "perform service discovery", err)
+			return
+		}
+		tflog.Debug(ctx, "Discovered Terrareg services", map[string]interface{}{"registry_host": api.RegistryHost()})
+	}
+
+	// Detect the version and configuration of Terrareg, so that resources
+	// can report unsupported features during plan
+	err = api.DetectCapabilities(ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Unable to detect Terrareg capabilities",
+			"The version and configuration of Terrareg could not be determined. "+
+				"Features that are not supported by the Terrareg server will fail when applied.\n\n"+
+				err.Error(),
+		)
+	}
+	if capabilities := api.Capabilities(); capabilities.Config != nil {
+		if data.UploadApiKey.ValueString() != "" && capabilities.Config.UploadApiKeysEnabled != nil && !*capabilities.Config.UploadApiKeysEnabled {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("upload_api_key"),
+				"Upload API keys are not enabled",
+				"An upload API key has been provided, but upload API keys are not enabled in Terrareg (UPLOAD_API_KEYS).",
+			)
+		}
+		if data.PublishApiKey.ValueString() != "" && capabilities.Config.PublishApiKeysEnabled != nil && !*capabilities.Config.PublishApiKeysEnabled {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("publish_api_key"),
+				"Publish API keys are not enabled",
+				"A publish API key has been provided, but publish API keys are not enabled in Terrareg (PUBLISH_API_KEYS).",
+			)
+		}
+	}
+	if data.VerifyCredentials.ValueBool() && !api.HasAdminCredentials() {
+		// Terrareg can only verify admin and Terraform CLI credentials
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("verify_credentials"),
+			"Unable to verify Terrareg credentials",
+			"Credentials are only verified when an admin token (or api_key) or Terraform CLI credentials for the Terrareg host are provided. "+
+				"Upload and publish API keys are not verified until they are used.",
+		)
+	} else if data.VerifyCredentials.ValueBool() {
+		authStatus, err := api.GetAuthenticationStatus(ctx)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "verify credentials", err)
+			return
+		}
+		if !authStatus.Authenticated {
+			resp.Diagnostics.AddError(
+				"Invalid Terrareg Credentials",
+				"The credentials provided to the provider were rejected by Terrareg. "+
+					"Check the value of admin_token (or api_key) or the Terraform CLI credentials for the Terrareg host.",
+			)
+			return
+		}
+	}
+
+	if capabilities := api.Capabilities(); capabilities.Version != nil {
+		tflog.Info(ctx, "Detected Terrareg version", map[string]interface{}{"version": capabilities.Version.String()})
+	}
 
 	resp.DataSourceData = api
 	resp.ResourceData = api
@@ -110,6 +482,9 @@ func (p *TerraregProvider) DataSources(ctx context.Context) []func() datasource.
 	return []func() datasource.DataSource{
 		NewGitProvidersDataSource,
 		NewGitProviderDataSource,
+		NewCurrentIdentityDataSource,
+		NewNamespaceDataSource,
+		NewNamespacesDataSource,
 	}
 }
 
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Environment variables used for provider attributes
+// that have not been set in the provider configuration.
+const (
+	envUrl                   = "TERRAREG_URL"
+	envApiKey                = "TERRAREG_API_KEY"
+	envAdminToken            = "TERRAREG_ADMIN_TOKEN"
+	envUploadApiKey          = "TERRAREG_UPLOAD_API_KEY"
+	envPublishApiKey         = "TERRAREG_PUBLISH_API_KEY"
+	envMaxRetries            = "TERRAREG_MAX_RETRIES"
+	envRetryWaitMin          = "TERRAREG_RETRY_WAIT_MIN"
+	envRetryWaitMax          = "TERRAREG_RETRY_WAIT_MAX"
+	envMaxIdleConnections    = "TERRAREG_MAX_IDLE_CONNECTIONS"
+	envRequestTimeout        = "TERRAREG_REQUEST_TIMEOUT"
+	envRateLimit             = "TERRAREG_RATE_LIMIT"
+	envRateLimitBurst        = "TERRAREG_RATE_LIMIT_BURST"
+	envMaxConcurrentRequests = "TERRAREG_MAX_CONCURRENT_REQUESTS"
+	envCacheTTL              = "TERRAREG_CACHE_TTL"
+	envCACertPEM             = "TERRAREG_CA_CERT_PEM"
+	envCACertFile            = "TERRAREG_CA_CERT_FILE"
+	envClientCert            = "TERRAREG_CLIENT_CERT"
+	envClientKey             = "TERRAREG_CLIENT_KEY"
+	envInsecureSkipVerify    = "TERRAREG_INSECURE_SKIP_VERIFY"
+	envProxyUrl              = "TERRAREG_PROXY_URL"
+	envVerifyCredentials     = "TERRAREG_VERIFY_CREDENTIALS"
+	envServiceDiscovery      = "TERRAREG_SERVICE_DISCOVERY"
+	envStrictApiDecoding     = "TERRAREG_STRICT_API_DECODING"
+)
+
+// envDescription returns the suffix added to attribute descriptions
+// for attributes that can be set using an environment variable.
+func envDescription(envVar string) string {
+	return fmt.Sprintf(" Can also be set using the `%s` environment variable.", envVar)
+}
+
+// applyEnvironment populates any attributes that have not been set in the
+// provider configuration from their respective environment variables.
+func (data *TerraregProviderModel) applyEnvironment(diags *diag.Diagnostics) {
+	stringFromEnv(&data.Url, envUrl)
+	stringFromEnv(&data.UploadApiKey, envUploadApiKey)
+	stringFromEnv(&data.PublishApiKey, envPublishApiKey)
+
+	// Only obtain the admin token from the environment if neither
+	// the admin token or api_key have been configured.
+	if data.AdminToken.IsNull() && data.ApiKey.IsNull() {
+		stringFromEnv(&data.AdminToken, envAdminToken)
+		if data.AdminToken.IsNull() {
+			stringFromEnv(&data.ApiKey, envApiKey)
+		}
+	}
+
+	stringFromEnv(&data.ClientCert, envClientCert)
+	stringFromEnv(&data.ClientKey, envClientKey)
+	stringFromEnv(&data.ProxyUrl, envProxyUrl)
+
+	// Only obtain CA certificate from environment if
+	// neither attribute has been configured.
+	if data.CACertPEM.IsNull() && data.CACertFile.IsNull() {
+		stringFromEnv(&data.CACertPEM, envCACertPEM)
+		if data.CACertPEM.IsNull() {
+			stringFromEnv(&data.CACertFile, envCACertFile)
+		}
+	}
+
+	int64FromEnv(&data.MaxRetries, envMaxRetries, path.Root("max_retries"), diags)
+	int64FromEnv(&data.RetryWaitMin, envRetryWaitMin, path.Root("retry_wait_min"), diags)
+	int64FromEnv(&data.RetryWaitMax, envRetryWaitMax, path.Root("retry_wait_max"), diags)
+	int64FromEnv(&data.MaxIdleConnections, envMaxIdleConnections, path.Root("max_idle_connections"), diags)
+	int64FromEnv(&data.RequestTimeout, envRequestTimeout, path.Root("request_timeout"), diags)
+	float64FromEnv(&data.RateLimit, envRateLimit, path.Root("rate_limit"), diags)
+	int64FromEnv(&data.RateLimitBurst, envRateLimitBurst, path.Root("rate_limit_burst"), diags)
+	int64FromEnv(&data.MaxConcurrentRequests, envMaxConcurrentRequests, path.Root("max_concurrent_requests"), diags)
+	int64FromEnv(&data.CacheTTL, envCacheTTL, path.Root("cache_ttl"), diags)
+
+	boolFromEnv(&data.InsecureSkipVerify, envInsecureSkipVerify, path.Root("insecure_skip_verify"), diags)
+	boolFromEnv(&data.VerifyCredentials, envVerifyCredentials, path.Root("verify_credentials"), diags)
+	boolFromEnv(&data.ServiceDiscovery, envServiceDiscovery, path.Root("service_discovery"), diags)
+	boolFromEnv(&data.StrictApiDecoding, envStrictApiDecoding, path.Root("strict_api_decoding"), diags)
+}
+
+func stringFromEnv(value *types.String, envVar string) {
+	if !value.IsNull() {
+		return
+	}
+	if envValue, ok := os.LookupEnv(envVar); ok && envValue != "" {
+		*value = types.StringValue(envValue)
+	}
+}
+
+func int64FromEnv(value *types.Int64, envVar string, attributePath path.Path, diags *diag.Diagnostics) {
+	if !value.IsNull() {
+		return
+	}
+	envValue, ok := os.LookupEnv(envVar)
+	if !ok || envValue == "" {
+		return
+	}
+	intValue, err := strconv.ParseInt(envValue, 10, 64)
+	if err != nil || intValue < 0 {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid environment variable value",
+			fmt.Sprintf("The %s environment variable must be a non-negative integer, got: %q", envVar, envValue),
+		)
+		return
+	}
+	*value = types.Int64Value(intValue)
+}
+
+func float64FromEnv(value *types.Float64, envVar string, attributePath path.Path, diags *diag.Diagnostics) {
+	if !value.IsNull() {
+		return
+	}
+	envValue, ok := os.LookupEnv(envVar)
+	if !ok || envValue == "" {
+		return
+	}
+	floatValue, err := strconv.ParseFloat(envValue, 64)
+	if err != nil || floatValue < 0 {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid environment variable value",
+			fmt.Sprintf("The %s environment variable must be a non-negative number, got: %q", envVar, envValue),
+		)
+		return
+	}
+	*value = types.Float64Value(floatValue)
+}
+
+func boolFromEnv(value *types.Bool, envVar string, attributePath path.Path, diags *diag.Diagnostics) {
+	if !value.IsNull() {
+		return
+	}
+	envValue, ok := os.LookupEnv(envVar)
+	if !ok || envValue == "" {
+		return
+	}
+	boolValue, err := strconv.ParseBool(envValue)
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid environment variable value",
+			fmt.Sprintf("The %s environment variable must be a boolean, got: %q", envVar, envValue),
+		)
+		return
+	}
+	*value = types.BoolValue(boolValue)
+}
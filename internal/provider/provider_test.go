@@ -7,6 +7,9 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/dockstudios/terraform-provider-terrareg/internal/terraregtest"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -17,6 +20,37 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 	"terrareg": providerserver.NewProtocol6WithError(New("test")()),
 }
 
+// testAccServer is the fake Terrareg server used by acceptance tests,
+// or nil if the tests are running against Terrareg (TERRAREG_URL).
+var testAccServer *terraregtest.Server
+
+func TestMain(m *testing.M) {
+	// Run the tests against a fake Terrareg server,
+	// unless a Terrareg instance has been provided
+	if _, ok := os.LookupEnv("TERRAREG_URL"); !ok {
+		testAccServer = terraregtest.NewServer("unittest-api-key")
+		os.Setenv("TERRAREG_URL", testAccServer.URL)
+		os.Setenv("TERRAREG_API_KEY", testAccServer.AdminToken)
+	}
+
+	code := m.Run()
+
+	if testAccServer != nil {
+		testAccServer.Close()
+	}
+	os.Exit(code)
+}
+
+// testAccFakeServer returns the fake Terrareg server, skipping the
+// test if the tests are running against a Terrareg instance.
+func testAccFakeServer(t *testing.T) *terraregtest.Server {
+	if testAccServer == nil {
+		t.Skip("Test requires the fake Terrareg server, but TERRAREG_URL is set")
+	}
+	t.Cleanup(testAccServer.ClearFaults)
+	return testAccServer
+}
+
 func testAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
@@ -31,11 +65,85 @@ func getEnv(key, defaultVal string) string {
 	return defaultVal
 }
 
+func TestAccProvider_environment_variables(t *testing.T) {
+	t.Setenv("TERRAREG_URL", getEnv("TERRAREG_URL", "http://localhost:5000"))
+	t.Setenv("TERRAREG_ADMIN_TOKEN", getEnv("TERRAREG_API_KEY", "unittest-api-key"))
+	t.Setenv("TERRAREG_RATE_LIMIT", "50")
+	t.Setenv("TERRAREG_MAX_CONCURRENT_REQUESTS", "2")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "terrareg" {}
+
+data "terrareg_git_providers" "this" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_git_providers.this", "git_providers.0.name", "Github"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_verify_credentials_scoped_keys(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.UploadApiKey = "unittest-upload-key"
+	server.PublishApiKey = "unittest-publish-key"
+	t.Cleanup(func() {
+		server.UploadApiKey = ""
+		server.PublishApiKey = ""
+	})
+
+	// Only provide the scoped keys, without an admin token
+	t.Setenv("TERRAREG_API_KEY", "")
+	t.Setenv("TERRAREG_ADMIN_TOKEN", "")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Credentials that cannot be verified do not fail configuration
+			{
+				Config: fmt.Sprintf(`
+provider "terrareg" {
+  url                = %q
+  upload_api_key     = "unittest-upload-key"
+  publish_api_key    = "unittest-publish-key"
+  verify_credentials = true
+}
+
+data "terrareg_git_providers" "this" {}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.terrareg_git_providers.this", "git_providers.0.name", "Github"),
+				),
+			},
+		},
+	})
+}
+
+// testAccClient creates a Terrareg client, using the same configuration
+// as the provider under test, for modifying objects outside of Terraform.
+func testAccClient(t *testing.T) *terrareg.TerraregClient {
+	client, err := terrareg.NewClient(
+		getEnv("TERRAREG_URL", "http://localhost:5000"),
+		terrareg.WithAdminToken(getEnv("TERRAREG_API_KEY", "unittest-api-key")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
 func buildTestProviderConfig(main string) string {
 	return fmt.Sprintf(`
 provider "terrareg" {
-  url = %[1]q
-  api_key = %[2]q
+  url         = %[1]q
+  admin_token = %[2]q
 }
 
 %[3]s
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+var _ validator.String = nameValidator{}
+
+// nameValidator validates that a string attribute is a valid
+// name in Terrareg, using one of the terrareg.Validate functions.
+type nameValidator struct {
+	description string
+	validate    func(string) error
+}
+
+func (v nameValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v nameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.description
+}
+
+func (v nameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := v.validate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Name", err.Error())
+	}
+}
+
+func namespaceNameValidator() validator.String {
+	return nameValidator{
+		description: "must be a valid Terrareg namespace name",
+		validate:    terrareg.ValidateNamespaceName,
+	}
+}
+
+func moduleNameValidator() validator.String {
+	return nameValidator{
+		description: "must be a valid Terrareg module name",
+		validate:    terrareg.ValidateModuleName,
+	}
+}
+
+func moduleProviderNameValidator() validator.String {
+	return nameValidator{
+		description: "must be a valid Terrareg module provider name",
+		validate:    terrareg.ValidateModuleProviderName,
+	}
+}
+
+var _ validator.String = regexValidator{}
+
+// regexValidator validates that a string attribute
+// is a valid regular expression.
+type regexValidator struct{}
+
+func (v regexValidator) Description(ctx context.Context) string {
+	return "must be a valid regular expression"
+}
+
+func (v regexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Regular Expression", err.Error())
+	}
+}
@@ -0,0 +1,405 @@
+package terraregtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) getConfig(w http.ResponseWriter) {
+	writeJson(w, map[string]any{
+		"ALLOW_CUSTOM_GIT_URL_MODULE_PROVIDER": s.AllowCustomGitUrls,
+		"UPLOAD_API_KEYS_ENABLED":              false,
+		"PUBLISH_API_KEYS_ENABLED":             false,
+	})
+}
+
+func (s *Server) getAuthenticationStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.isAuthenticated(r) {
+		writeError(w, http.StatusUnauthorized, "Not authenticated")
+		return
+	}
+	writeJson(w, map[string]any{
+		"authenticated":         true,
+		"read_access":           true,
+		"site_admin":            true,
+		"namespace_permissions": map[string]string{},
+	})
+}
+
+func (s *Server) listNamespaces(w http.ResponseWriter) {
+	names := make([]string, 0, len(s.namespaces))
+	for name := range s.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		data = append(data, map[string]string{
+			"name":         name,
+			"display_name": s.namespaces[name].DisplayName,
+		})
+	}
+	writeJson(w, data)
+}
+
+type namespaceConfig struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+func (s *Server) createNamespace(w http.ResponseWriter, r *http.Request) {
+	var config namespaceConfig
+	if !decodeRequest(w, r, &config) {
+		return
+	}
+	if config.Name == "" {
+		writeError(w, http.StatusBadRequest, "A name must be provided")
+		return
+	}
+	if _, ok := s.namespaces[config.Name]; ok {
+		writeError(w, http.StatusBadRequest, "A namespace already exists with this name")
+		return
+	}
+	s.namespaces[config.Name] = &namespace{
+		Name:        config.Name,
+		DisplayName: config.DisplayName,
+	}
+	writeJson(w, map[string]string{"name": config.Name})
+}
+
+func (s *Server) getNamespace(w http.ResponseWriter, name string) {
+	ns, ok := s.namespaces[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Namespace does not exist")
+		return
+	}
+	writeJson(w, map[string]any{
+		"display_name":     ns.DisplayName,
+		"is_auto_verified": ns.IsAutoVerified,
+		"trusted":          ns.Trusted,
+	})
+}
+
+func (s *Server) updateNamespace(w http.ResponseWriter, r *http.Request, name string) {
+	ns, ok := s.namespaces[name]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Namespace does not exist")
+		return
+	}
+	var config namespaceConfig
+	if !decodeRequest(w, r, &config) {
+		return
+	}
+
+	if config.Name != "" && config.Name != name {
+		if _, ok := s.namespaces[config.Name]; ok {
+			writeError(w, http.StatusBadRequest, "A namespace already exists with this name")
+			return
+		}
+		delete(s.namespaces, name)
+		ns.Name = config.Name
+		s.namespaces[ns.Name] = ns
+
+		// Move the modules of the namespace
+		for id, module := range s.modules {
+			if module.Namespace == name {
+				delete(s.modules, id)
+				module.Namespace = ns.Name
+				s.modules[module.id()] = module
+			}
+		}
+	}
+	ns.DisplayName = config.DisplayName
+	writeJson(w, map[string]string{"name": ns.Name})
+}
+
+func (s *Server) deleteNamespace(w http.ResponseWriter, name string) {
+	if _, ok := s.namespaces[name]; !ok {
+		writeError(w, http.StatusBadRequest, "Namespace does not exist")
+		return
+	}
+	for _, module := range s.modules {
+		if module.Namespace == name {
+			writeError(w, http.StatusBadRequest, "Namespace cannot be deleted as it contains modules")
+			return
+		}
+	}
+	delete(s.namespaces, name)
+	writeJson(w, map[string]string{})
+}
+
+// Default number of module providers returned per page
+const defaultPageSize = 10
+
+func (s *Server) listNamespaceModules(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.namespaces[name]; !ok {
+		writeError(w, http.StatusBadRequest, "Namespace does not exist")
+		return
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	var ids []string
+	for id, module := range s.modules {
+		if module.Namespace == name {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	meta := map[string]any{
+		"limit":          limit,
+		"current_offset": offset,
+	}
+	if offset > 0 {
+		meta["prev_offset"] = offset - limit
+		if offset < limit {
+			meta["prev_offset"] = 0
+		}
+	}
+	if offset+limit < len(ids) {
+		meta["next_offset"] = offset + limit
+	}
+
+	start, end := offset, offset+limit
+	if start > len(ids) {
+		start = len(ids)
+	}
+	if end > len(ids) {
+		end = len(ids)
+	}
+	modules := make([]map[string]any, 0, end-start)
+	for _, id := range ids[start:end] {
+		module := s.modules[id]
+		modules = append(modules, map[string]any{
+			"id":        module.id(),
+			"namespace": module.Namespace,
+			"name":      module.Name,
+			"provider":  module.Provider,
+		})
+	}
+	writeJson(w, map[string]any{"meta": meta, "modules": modules})
+}
+
+// findModule returns the module provider identified by the namespace,
+// name and provider, writing an error response if it does not exist.
+func (s *Server) findModule(w http.ResponseWriter, id []string) (*moduleProvider, bool) {
+	if _, ok := s.namespaces[id[0]]; !ok {
+		writeError(w, http.StatusBadRequest, "Namespace does not exist")
+		return nil, false
+	}
+	module, ok := s.modules[id[0]+"/"+id[1]+"/"+id[2]]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Module provider does not exist")
+		return nil, false
+	}
+	return module, true
+}
+
+// applySettings updates the settings of a module with the
+// settings provided in a request, validating them first.
+func (s *Server) applySettings(w http.ResponseWriter, module *moduleProvider, settings moduleSettings) bool {
+	if settings.GitProviderID != nil && *settings.GitProviderID != 0 {
+		found := false
+		for _, gitProvider := range s.GitProviders {
+			found = found || gitProvider.ID == *settings.GitProviderID
+		}
+		if !found {
+			writeError(w, http.StatusBadRequest, "Git provider does not exist")
+			return false
+		}
+	}
+	if !s.AllowCustomGitUrls {
+		for _, template := range []*string{settings.RepoBaseUrlTemplate, settings.RepoCloneUrlTemplate, settings.RepoBrowseUrlTemplate} {
+			if template != nil && *template != "" {
+				writeError(w, http.StatusBadRequest, "Custom module provider git URLs are not allowed")
+				return false
+			}
+		}
+	}
+
+	if settings.GitProviderID != nil {
+		module.Settings.GitProviderID = settings.GitProviderID
+	}
+	if settings.RepoBaseUrlTemplate != nil {
+		module.Settings.RepoBaseUrlTemplate = settings.RepoBaseUrlTemplate
+	}
+	if settings.RepoCloneUrlTemplate != nil {
+		module.Settings.RepoCloneUrlTemplate = settings.RepoCloneUrlTemplate
+	}
+	if settings.RepoBrowseUrlTemplate != nil {
+		module.Settings.RepoBrowseUrlTemplate = settings.RepoBrowseUrlTemplate
+	}
+	if settings.GitTagFormat != nil {
+		module.Settings.GitTagFormat = settings.GitTagFormat
+	}
+	if settings.GitPath != nil {
+		module.Settings.GitPath = settings.GitPath
+	}
+	return true
+}
+
+func (s *Server) createModule(w http.ResponseWriter, r *http.Request, id []string) {
+	if _, ok := s.namespaces[id[0]]; !ok {
+		writeError(w, http.StatusBadRequest, "Namespace does not exist")
+		return
+	}
+	module := &moduleProvider{Namespace: id[0], Name: id[1], Provider: id[2], Versions: make(map[string]bool)}
+	if _, ok := s.modules[module.id()]; ok {
+		writeError(w, http.StatusBadRequest, "Module provider already exists")
+		return
+	}
+
+	var settings moduleSettings
+	if !decodeRequest(w, r, &settings) {
+		return
+	}
+	if !s.applySettings(w, module, settings) {
+		return
+	}
+	s.modules[module.id()] = module
+	writeJson(w, map[string]string{"id": module.id()})
+}
+
+func (s *Server) getModule(w http.ResponseWriter, id []string) {
+	module, ok := s.findModule(w, id)
+	if !ok {
+		return
+	}
+	writeJson(w, map[string]any{
+		"id":                       module.id(),
+		"namespace":                module.Namespace,
+		"name":                     module.Name,
+		"provider":                 module.Provider,
+		"git_provider_id":          module.Settings.GitProviderID,
+		"repo_base_url_template":   module.Settings.RepoBaseUrlTemplate,
+		"repo_clone_url_template":  module.Settings.RepoCloneUrlTemplate,
+		"repo_browse_url_template": module.Settings.RepoBrowseUrlTemplate,
+		"git_tag_format":           module.Settings.GitTagFormat,
+		"git_path":                 module.Settings.GitPath,
+	})
+}
+
+func (s *Server) updateModule(w http.ResponseWriter, r *http.Request, id []string) {
+	module, ok := s.findModule(w, id)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		moduleSettings
+		Namespace string `json:"namespace"`
+		Name      string `json:"module"`
+		Provider  string `json:"provider"`
+	}
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	// Validate renaming the module before applying any changes
+	renamed := *module
+	if request.Namespace != "" {
+		renamed.Namespace = request.Namespace
+	}
+	if request.Name != "" {
+		renamed.Name = request.Name
+	}
+	if request.Provider != "" {
+		renamed.Provider = request.Provider
+	}
+	if renamed.id() != module.id() {
+		if _, ok := s.namespaces[renamed.Namespace]; !ok {
+			writeError(w, http.StatusBadRequest, "Namespace does not exist")
+			return
+		}
+		if _, ok := s.modules[renamed.id()]; ok {
+			writeError(w, http.StatusBadRequest, "Module provider already exists")
+			return
+		}
+	}
+
+	if !s.applySettings(w, &renamed, request.moduleSettings) {
+		return
+	}
+	delete(s.modules, module.id())
+	s.modules[renamed.id()] = &renamed
+	writeJson(w, map[string]string{})
+}
+
+func (s *Server) deleteModule(w http.ResponseWriter, id []string) {
+	module, ok := s.findModule(w, id)
+	if !ok {
+		return
+	}
+	delete(s.modules, module.id())
+	writeJson(w, map[string]string{})
+}
+
+func (s *Server) importModuleVersion(w http.ResponseWriter, r *http.Request, id []string) {
+	module, ok := s.findModule(w, id)
+	if !ok {
+		return
+	}
+	var request struct {
+		Version string `json:"version"`
+		GitTag  string `json:"git_tag"`
+	}
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	// Only git tag formats in the form of a prefixed version
+	// are supported for importing by git tag
+	version := request.Version
+	if version == "" && request.GitTag != "" && module.Settings.GitTagFormat != nil {
+		prefix, _, _ := strings.Cut(*module.Settings.GitTagFormat, "{version}")
+		version = strings.TrimPrefix(request.GitTag, prefix)
+	}
+	if version == "" {
+		writeError(w, http.StatusBadRequest, "Either version or git_tag must be provided")
+		return
+	}
+	if _, ok := module.Versions[version]; !ok {
+		module.Versions[version] = false
+	}
+	writeJson(w, map[string]string{"status": "Success"})
+}
+
+func (s *Server) uploadModuleVersion(w http.ResponseWriter, r *http.Request, id []string, version string) {
+	module, ok := s.findModule(w, id)
+	if !ok {
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Module archive must be uploaded as the 'file' form field")
+		return
+	}
+	file.Close()
+	if _, ok := module.Versions[version]; !ok {
+		module.Versions[version] = false
+	}
+	writeJson(w, map[string]string{"status": "Success"})
+}
+
+func (s *Server) publishModuleVersion(w http.ResponseWriter, id []string, version string) {
+	module, ok := s.findModule(w, id)
+	if !ok {
+		return
+	}
+	if _, ok := module.Versions[version]; !ok {
+		writeError(w, http.StatusBadRequest, "Module version does not exist")
+		return
+	}
+	module.Versions[version] = true
+	writeJson(w, map[string]string{"status": "Success"})
+}
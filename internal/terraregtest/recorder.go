@@ -0,0 +1,404 @@
+package terraregtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RecorderMode determines whether a Recorder records or replays requests.
+type RecorderMode int
+
+const (
+	// ModeReplay replays the responses from a fixture file, failing any
+	// request that does not match the next recorded request.
+	ModeReplay RecorderMode = iota
+	// ModeRecord sends requests using the underlying transport,
+	// writing the requests and responses to a fixture file.
+	ModeRecord
+)
+
+// Placeholder values stored in fixture files in place of request header
+// values. Credentials that have not been registered using RedactCredential
+// are stored as redactedHeaderValue. Headers that differ between requests
+// are stored as variableHeaderValue, which matches any value when replaying.
+const (
+	redactedHeaderValue = "***"
+	variableHeaderValue = "*"
+)
+
+// Request headers that contain credentials, which are never recorded
+var redactedHeaders = []string{
+	"Authorization",
+	"X-Terrareg-ApiKey",
+	"X-Terrareg-Upload-Key",
+	"X-Terrareg-Publish-Key",
+}
+
+// Request headers that differ for every request
+var variableHeaders = []string{
+	"X-Request-ID",
+}
+
+// Placeholder stored in place of the random boundary of multipart requests
+const multipartBoundary = "BOUNDARY"
+
+// RecordedRequest is a request stored in a fixture file.
+type RecordedRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response stored in a fixture file.
+type RecordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+}
+
+// Interaction is a request and its response, stored in a fixture file.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Recorder is an http.RoundTripper that records requests and responses
+// to a fixture file, or replays the responses from a fixture file whilst
+// asserting that the same requests are made.
+//
+// Requests are recorded relative to the host, so that fixture files can be
+// recorded against a Terrareg instance and replayed using any URL.
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	credentials  map[string]string
+	interactions []Interaction
+	replayed     int
+	errs         []error
+}
+
+// NewRecorder creates a Recorder for the fixture file at the path. In
+// ModeRecord, requests are sent using the transport, and the fixture file
+// is written when the Recorder is closed. In ModeReplay, the fixture file
+// is read immediately and the transport is not used.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+	}
+	if mode == ModeRecord {
+		if r.transport == nil {
+			r.transport = http.DefaultTransport
+		}
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture file: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("unable to parse fixture file %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// RedactCredential stores the placeholder (e.g. "<admin>") in place of the
+// credential in request headers. Requests are replayed only if they send
+// the credential registered with the same placeholder as the recorded
+// request, so that fixture files can be recorded and replayed using
+// different credentials, whilst asserting which credential is used.
+func (r *Recorder) RedactCredential(value string, placeholder string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if value == "" {
+		return
+	}
+	if r.credentials == nil {
+		r.credentials = make(map[string]string)
+	}
+	r.credentials[value] = placeholder
+}
+
+// Mode returns whether the recorder is recording or replaying requests.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := r.recordRequest(req, body)
+
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	response := RecordedResponse{
+		StatusCode: res.StatusCode,
+		Body:       encodeBody(res.Header.Get("Content-Type"), body),
+	}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		response.Headers = map[string]string{"Content-Type": contentType}
+	}
+	r.interactions = append(r.interactions, Interaction{Request: recorded, Response: response})
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	if r.replayed >= len(r.interactions) {
+		err := fmt.Errorf("unexpected request %s %s: all %d recorded requests have been replayed", recorded.Method, recorded.Path, len(r.interactions))
+		r.errs = append(r.errs, err)
+		return nil, err
+	}
+	interaction := r.interactions[r.replayed]
+	r.replayed++
+
+	if err := matchRequest(interaction.Request, recorded); err != nil {
+		err = fmt.Errorf("request %d (%s %s) does not match fixture file: %w", r.replayed, recorded.Method, recorded.Path, err)
+		r.errs = append(r.errs, err)
+		return nil, err
+	}
+
+	header := make(http.Header)
+	for name, value := range interaction.Response.Headers {
+		header.Set(name, value)
+	}
+	body, err := decodeBody(interaction.Response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Close writes the fixture file when recording. When replaying, it returns
+// an error if any request did not match the fixture file, or if any
+// recorded requests were not made.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeRecord {
+		// Avoid escaping placeholders (e.g. "<admin>"), so that
+		// fixture files remain readable
+		var data bytes.Buffer
+		encoder := json.NewEncoder(&data)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r.interactions); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(r.path, data.Bytes(), 0o644)
+	}
+
+	errs := r.errs
+	if r.replayed < len(r.interactions) {
+		next := r.interactions[r.replayed].Request
+		errs = append(errs, fmt.Errorf("%d recorded requests were not made, starting with %s %s", len(r.interactions)-r.replayed, next.Method, next.Path))
+	}
+	return errors.Join(errs...)
+}
+
+// recordRequest converts a request to the form stored in fixture files,
+// replacing sensitive and variable values.
+func (r *Recorder) recordRequest(req *http.Request, body []byte) RecordedRequest {
+	contentType := req.Header.Get("Content-Type")
+	boundary := ""
+	if mediaType, params, err := mime.ParseMediaType(contentType); err == nil && strings.HasPrefix(mediaType, "multipart/") {
+		boundary = params["boundary"]
+	}
+
+	headers := make(map[string]string, len(req.Header))
+	for name := range req.Header {
+		value := req.Header.Get(name)
+		switch {
+		case containsHeader(redactedHeaders, name):
+			value = r.redactCredential(value)
+		case containsHeader(variableHeaders, name):
+			value = variableHeaderValue
+		case boundary != "":
+			value = strings.ReplaceAll(value, boundary, multipartBoundary)
+		}
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	if boundary != "" {
+		body = bytes.ReplaceAll(body, []byte(boundary), []byte(multipartBoundary))
+	}
+
+	return RecordedRequest{
+		Method:  req.Method,
+		Path:    req.URL.RequestURI(),
+		Headers: headers,
+		Body:    encodeBody(contentType, body),
+	}
+}
+
+// redactCredential replaces the registered credential contained in the
+// header value (e.g. "Bearer <token>") with its placeholder, or the whole
+// value with redactedHeaderValue if no registered credential is contained.
+func (r *Recorder) redactCredential(value string) string {
+	// Replace the longest credentials first, in case
+	// a credential contains another credential
+	credentials := make([]string, 0, len(r.credentials))
+	for credential := range r.credentials {
+		credentials = append(credentials, credential)
+	}
+	sort.Slice(credentials, func(i, j int) bool {
+		return len(credentials[i]) > len(credentials[j])
+	})
+	for _, credential := range credentials {
+		if strings.Contains(value, credential) {
+			return strings.ReplaceAll(value, credential, r.credentials[credential])
+		}
+	}
+	return redactedHeaderValue
+}
+
+// matchRequest returns an error describing the differences
+// between the recorded request and the actual request.
+func matchRequest(expected RecordedRequest, actual RecordedRequest) error {
+	var errs []error
+	if expected.Method != actual.Method || expected.Path != actual.Path {
+		errs = append(errs, fmt.Errorf("expected %s %s", expected.Method, expected.Path))
+	}
+
+	names := make(map[string]bool)
+	for name := range expected.Headers {
+		names[name] = true
+	}
+	for name := range actual.Headers {
+		names[name] = true
+	}
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+	for _, name := range sortedNames {
+		expectedValue, expectedOk := expected.Headers[name]
+		actualValue, actualOk := actual.Headers[name]
+		switch {
+		case !expectedOk:
+			errs = append(errs, fmt.Errorf("unexpected header %s", name))
+		case !actualOk:
+			errs = append(errs, fmt.Errorf("missing header %s", name))
+		case expectedValue == variableHeaderValue:
+			// Any value is accepted
+		case expectedValue != actualValue:
+			errs = append(errs, fmt.Errorf("expected header %s to be %q, got %q", name, expectedValue, actualValue))
+		}
+	}
+
+	if !equalBodies(expected.Body, actual.Body) {
+		errs = append(errs, fmt.Errorf("expected body %s, got %s", expected.Body, actual.Body))
+	}
+	return errors.Join(errs...)
+}
+
+// equalBodies compares JSON bodies, ignoring formatting.
+func equalBodies(expected json.RawMessage, actual json.RawMessage) bool {
+	if len(expected) == 0 || len(actual) == 0 {
+		return len(expected) == len(actual)
+	}
+	var expectedValue, actualValue any
+	if json.Unmarshal(expected, &expectedValue) != nil || json.Unmarshal(actual, &actualValue) != nil {
+		return bytes.Equal(expected, actual)
+	}
+	return reflect.DeepEqual(expectedValue, actualValue)
+}
+
+// encodeBody converts a body to JSON for storing in a fixture file.
+// JSON bodies are stored as-is, and other bodies are stored as a string.
+func encodeBody(contentType string, body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if strings.HasPrefix(contentType, "application/json") && json.Valid(body) {
+		var compacted bytes.Buffer
+		if json.Compact(&compacted, body) == nil {
+			return compacted.Bytes()
+		}
+	}
+	encoded, _ := json.Marshal(string(body))
+	return encoded
+}
+
+// decodeBody converts a body stored in a fixture file back to its raw form.
+func decodeBody(body json.RawMessage) ([]byte, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	var text string
+	if body[0] == '"' {
+		if err := json.Unmarshal(body, &text); err != nil {
+			return nil, err
+		}
+		return []byte(text), nil
+	}
+	return body, nil
+}
+
+func containsHeader(headers []string, name string) bool {
+	for _, header := range headers {
+		if http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package terraregtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+func newRecorderClient(t *testing.T, url string, recorder *Recorder) *terrareg.TerraregClient {
+	t.Helper()
+
+	client, err := terrareg.NewClient(
+		url,
+		terrareg.WithAdminToken("admin-token"),
+		terrareg.WithTransport(recorder),
+		terrareg.WithMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRecorder(t *testing.T) {
+	server := NewServer("admin-token")
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	ctx := context.Background()
+
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newRecorderClient(t, server.URL, recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example", DisplayName: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetNamespace(ctx, "example"); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying identical requests succeeds, without a server
+	recorder, err = NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = newRecorderClient(t, "http://terrareg.test", recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example", DisplayName: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+	namespace, err := client.GetNamespace(ctx, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if namespace.DisplayName != "Example" {
+		t.Errorf("unexpected display name %q", namespace.DisplayName)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestRecorderMismatch(t *testing.T) {
+	server := NewServer("admin-token")
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	ctx := context.Background()
+
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newRecorderClient(t, server.URL, recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example", DisplayName: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetNamespace(ctx, "example"); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder, err = NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = newRecorderClient(t, "http://terrareg.test", recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example", DisplayName: "Changed"}); err == nil {
+		t.Errorf("expected error for request with different body")
+	}
+
+	err = recorder.Close()
+	if err == nil {
+		t.Fatal("expected error closing recorder")
+	}
+	for _, expected := range []string{"expected body", "1 recorded requests were not made"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got: %s", expected, err)
+		}
+	}
+}
+
+func TestRecorderCredentials(t *testing.T) {
+	server := NewServer("admin-token")
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	ctx := context.Background()
+
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.RedactCredential("admin-token", "<admin>")
+	client := newRecorderClient(t, server.URL, recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "admin-token") {
+		t.Errorf("credential recorded in fixture file: %s", data)
+	}
+	if !strings.Contains(string(data), `"X-Terrareg-Apikey": "<admin>"`) {
+		t.Errorf("expected credential placeholder in fixture file: %s", data)
+	}
+
+	// Replaying succeeds with a different credential registered with the same placeholder
+	recorder, err = NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.RedactCredential("admin-token", "<admin>")
+	client = newRecorderClient(t, "http://terrareg.test", recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Replaying fails when the credential is registered with a different placeholder
+	recorder, err = NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.RedactCredential("admin-token", "<upload>")
+	client = newRecorderClient(t, "http://terrareg.test", recorder)
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err == nil {
+		t.Errorf("expected error for request with different credential")
+	}
+	err = recorder.Close()
+	if err == nil || !strings.Contains(err.Error(), `expected header X-Terrareg-Apikey to be "<admin>", got "<upload>"`) {
+		t.Errorf("expected credential mismatch, got: %v", err)
+	}
+}
@@ -0,0 +1,328 @@
+// Package terraregtest provides a stateful, in-memory fake of the Terrareg
+// API, for testing the client and provider without a Terrareg instance.
+//
+// Only the endpoints used by the provider are implemented, and their
+// behaviour is modelled on Terrareg closely enough for the acceptance
+// tests, rather than reproducing every validation performed by Terrareg.
+package terraregtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultVersion is the version reported by the fake server, which is
+// deliberately not a Terrareg release, as the fake only implements the
+// subset of the API used by the provider.
+const DefaultVersion = "0.0.0-terraregtest"
+
+// Header containing the admin authentication token
+const headerAdminApiKey = "X-Terrareg-ApiKey"
+
+// Headers containing the API keys for uploading and publishing module versions
+const (
+	headerUploadApiKey  = "X-Terrareg-Upload-Key"
+	headerPublishApiKey = "X-Terrareg-Publish-Key"
+)
+
+// GitProvider is a git provider configured in the fake server.
+type GitProvider struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// DefaultGitProviders are the git providers configured in a new server,
+// matching the GIT_PROVIDER_CONFIG used for the acceptance tests.
+var DefaultGitProviders = []GitProvider{
+	{ID: 1, Name: "Github"},
+	{ID: 2, Name: "Bitbucket"},
+	{ID: 3, Name: "Gitlab"},
+}
+
+// Fault causes requests to the server to fail with a status code.
+type Fault struct {
+	// HTTP method of the requests to fail, or empty for all methods
+	Method string
+	// Path of the requests to fail (e.g. /v1/terrareg/namespaces),
+	// or empty for all paths
+	Path string
+	// Status code returned for the requests
+	StatusCode int
+	// Number of requests to fail, or 0 to fail all matching requests
+	Times int
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	return (f.Method == "" || f.Method == r.Method) && (f.Path == "" || f.Path == r.URL.Path)
+}
+
+type namespace struct {
+	Name           string
+	DisplayName    string
+	IsAutoVerified bool
+	Trusted        bool
+}
+
+type moduleSettings struct {
+	GitProviderID         *int64  `json:"git_provider_id"`
+	RepoBaseUrlTemplate   *string `json:"repo_base_url_template"`
+	RepoCloneUrlTemplate  *string `json:"repo_clone_url_template"`
+	RepoBrowseUrlTemplate *string `json:"repo_browse_url_template"`
+	GitTagFormat          *string `json:"git_tag_format"`
+	GitPath               *string `json:"git_path"`
+}
+
+type moduleProvider struct {
+	Namespace string
+	Name      string
+	Provider  string
+	Settings  moduleSettings
+	// Map of indexed versions to whether they have been published
+	Versions map[string]bool
+}
+
+func (m *moduleProvider) id() string {
+	return m.Namespace + "/" + m.Name + "/" + m.Provider
+}
+
+// Server is a fake Terrareg server, which stores namespaces and
+// modules in memory.
+type Server struct {
+	*httptest.Server
+
+	// AdminToken is the admin authentication token that must be provided
+	// to modify objects and to be reported as authenticated.
+	AdminToken string
+	// UploadApiKey, if set, may be provided instead of the admin
+	// token to import and upload module versions.
+	UploadApiKey string
+	// PublishApiKey, if set, may be provided instead of the admin
+	// token to publish module versions.
+	PublishApiKey string
+	// Version is the Terrareg version reported by the server.
+	Version string
+	// GitProviders are the git providers configured in the server.
+	GitProviders []GitProvider
+	// AllowCustomGitUrls reports whether modules may set
+	// custom repository URL templates.
+	AllowCustomGitUrls bool
+
+	mu         sync.Mutex
+	namespaces map[string]*namespace
+	modules    map[string]*moduleProvider
+	faults     []*Fault
+	requests   int
+}
+
+// NewServer starts a fake Terrareg server, which requires the admin
+// token for modifying objects. The server must be closed using Close.
+func NewServer(adminToken string) *Server {
+	s := &Server{
+		AdminToken:         adminToken,
+		Version:            DefaultVersion,
+		GitProviders:       append([]GitProvider{}, DefaultGitProviders...),
+		AllowCustomGitUrls: true,
+		namespaces:         make(map[string]*namespace),
+		modules:            make(map[string]*moduleProvider),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// InjectFault causes matching requests to fail with the fault's status code,
+// until the fault has been applied the requested number of times.
+// Faults are applied in the order they were injected.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of requests received by the server.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// SetNamespaceTrust sets the verification and trust of a namespace,
+// which cannot be modified using the API. It returns false if the
+// namespace does not exist.
+func (s *Server) SetNamespaceTrust(name string, isAutoVerified bool, trusted bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ns, ok := s.namespaces[name]
+	if !ok {
+		return false
+	}
+	ns.IsAutoVerified = isAutoVerified
+	ns.Trusted = trusted
+	return true
+}
+
+// injectedFault returns the status code of the first fault matching
+// the request, if any, recording that it has been applied.
+func (s *Server) injectedFault(r *http.Request) (int, bool) {
+	for i, fault := range s.faults {
+		if !fault.matches(r) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return fault.StatusCode, true
+	}
+	return 0, false
+}
+
+func (s *Server) isAuthenticated(r *http.Request) bool {
+	return s.AdminToken != "" && r.Header.Get(headerAdminApiKey) == s.AdminToken
+}
+
+// isAuthorized returns whether the request may modify objects, using
+// either the admin token or the API key for the action, if any.
+func (s *Server) isAuthorized(r *http.Request, segments []string) bool {
+	if s.isAuthenticated(r) {
+		return true
+	}
+	switch segments[len(segments)-1] {
+	case "import", "upload":
+		return s.UploadApiKey != "" && r.Header.Get(headerUploadApiKey) == s.UploadApiKey
+	case "publish":
+		return s.PublishApiKey != "" && r.Header.Get(headerPublishApiKey) == s.PublishApiKey
+	}
+	return false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	if statusCode, ok := s.injectedFault(r); ok {
+		writeError(w, statusCode, http.StatusText(statusCode))
+		return
+	}
+
+	if r.URL.Path == "/.well-known/terraform.json" {
+		writeJson(w, map[string]string{"modules.v1": "/v1/modules/"})
+		return
+	}
+
+	segments, ok := pathSegments(r.URL.EscapedPath(), "/v1/terrareg/")
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	// Modifying objects requires authentication, whereas reading
+	// objects is permitted for unauthenticated users
+	if r.Method != http.MethodGet && !s.isAuthorized(r, segments) {
+		writeError(w, http.StatusUnauthorized, "You must be logged in to perform this action")
+		return
+	}
+
+	switch {
+	case matchRoute(r, segments, http.MethodGet, "version"):
+		writeJson(w, map[string]string{"version": s.Version})
+	case matchRoute(r, segments, http.MethodGet, "config"):
+		s.getConfig(w)
+	case matchRoute(r, segments, http.MethodGet, "auth", "admin", "is_authenticated"):
+		s.getAuthenticationStatus(w, r)
+	case matchRoute(r, segments, http.MethodGet, "git_providers"):
+		writeJson(w, s.GitProviders)
+	case matchRoute(r, segments, http.MethodGet, "namespaces"):
+		s.listNamespaces(w)
+	case matchRoute(r, segments, http.MethodPost, "namespaces"):
+		s.createNamespace(w, r)
+	case matchRoute(r, segments, http.MethodGet, "namespaces", "*"):
+		s.getNamespace(w, segments[1])
+	case matchRoute(r, segments, http.MethodPost, "namespaces", "*"):
+		s.updateNamespace(w, r, segments[1])
+	case matchRoute(r, segments, http.MethodDelete, "namespaces", "*"):
+		s.deleteNamespace(w, segments[1])
+	case matchRoute(r, segments, http.MethodGet, "modules", "*"):
+		s.listNamespaceModules(w, r, segments[1])
+	case matchRoute(r, segments, http.MethodGet, "modules", "*", "*", "*"):
+		s.getModule(w, segments[1:4])
+	case matchRoute(r, segments, http.MethodPost, "modules", "*", "*", "*", "create"):
+		s.createModule(w, r, segments[1:4])
+	case matchRoute(r, segments, http.MethodPost, "modules", "*", "*", "*", "settings"):
+		s.updateModule(w, r, segments[1:4])
+	case matchRoute(r, segments, http.MethodDelete, "modules", "*", "*", "*", "delete"):
+		s.deleteModule(w, segments[1:4])
+	case matchRoute(r, segments, http.MethodPost, "modules", "*", "*", "*", "import"):
+		s.importModuleVersion(w, r, segments[1:4])
+	case matchRoute(r, segments, http.MethodPost, "modules", "*", "*", "*", "*", "upload"):
+		s.uploadModuleVersion(w, r, segments[1:4], segments[4])
+	case matchRoute(r, segments, http.MethodPost, "modules", "*", "*", "*", "*", "publish"):
+		s.publishModuleVersion(w, segments[1:4], segments[4])
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// pathSegments returns the unescaped segments of the
+// path following the prefix.
+func pathSegments(escapedPath string, prefix string) ([]string, bool) {
+	if !strings.HasPrefix(escapedPath, prefix) {
+		return nil, false
+	}
+	segments := strings.Split(strings.TrimSuffix(strings.TrimPrefix(escapedPath, prefix), "/"), "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, false
+		}
+		segments[i] = unescaped
+	}
+	return segments, true
+}
+
+// matchRoute returns whether the request matches the method and path
+// segments, where a "*" segment matches any value.
+func matchRoute(r *http.Request, segments []string, method string, route ...string) bool {
+	if r.Method != method || len(segments) != len(route) {
+		return false
+	}
+	for i, segment := range route {
+		if segment != "*" && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJson(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+// writeError writes an error in the format returned by Terrareg.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "Error", "message": message})
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON request: "+err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,324 @@
+package terraregtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+func newTestServer(t *testing.T) (*Server, *terrareg.TerraregClient) {
+	t.Helper()
+
+	server := NewServer("admin-token")
+	t.Cleanup(server.Close)
+
+	client, err := terrareg.NewClient(
+		server.URL,
+		terrareg.WithAdminToken("admin-token"),
+		terrareg.WithRetryWait(0, 0),
+		terrareg.WithCacheTTL(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, client
+}
+
+func TestNamespaces(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example", DisplayName: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err == nil {
+		t.Errorf("expected error creating duplicate namespace")
+	}
+
+	namespace, err := client.GetNamespace(ctx, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if namespace.DisplayName != "Example" {
+		t.Errorf("unexpected display name %q", namespace.DisplayName)
+	}
+
+	if err := client.UpdateNamespace(ctx, "example", terrareg.NamespaceConfigModel{Name: "renamed", DisplayName: "Renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetNamespace(ctx, "example"); !errors.Is(err, terrareg.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for renamed namespace, got %v", err)
+	}
+	namespaces, err := client.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 1 || namespaces[0].Name != "renamed" || namespaces[0].DisplayName != "Renamed" {
+		t.Errorf("unexpected namespaces %v", namespaces)
+	}
+
+	// Trust is only set outside of the API
+	if !server.SetNamespaceTrust("renamed", false, true) {
+		t.Fatal("expected namespace to exist")
+	}
+	namespace, err = client.GetNamespace(ctx, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !namespace.Trusted || namespace.IsAutoVerified {
+		t.Errorf("unexpected trust %+v", namespace)
+	}
+
+	if err := client.DeleteNamespace(ctx, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteNamespace(ctx, "renamed"); !errors.Is(err, terrareg.ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting namespace again, got %v", err)
+	}
+}
+
+func TestModules(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	id := terrareg.ModuleProviderID{Namespace: "example", Name: "vpc", Provider: "aws"}
+	if _, err := client.CreateModule(ctx, id, terrareg.ModuleModel{}); !errors.Is(err, terrareg.ErrNotFound) {
+		t.Errorf("expected ErrNotFound creating module without namespace, got %v", err)
+	}
+
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	createdId, err := client.CreateModule(ctx, id, terrareg.ModuleModel{GitProviderID: 3, GitTagFormat: "v{version}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if createdId != id {
+		t.Errorf("unexpected ID %v", createdId)
+	}
+	if _, err := client.CreateModule(ctx, id, terrareg.ModuleModel{GitProviderID: 10}); err == nil {
+		t.Errorf("expected error creating duplicate module")
+	}
+
+	module, err := client.GetModule(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.GitProviderID != 3 || module.GitTagFormat != "v{version}" {
+		t.Errorf("unexpected module settings %+v", module)
+	}
+
+	// Namespaces containing modules cannot be deleted
+	if err := client.DeleteNamespace(ctx, "example"); err == nil {
+		t.Errorf("expected error deleting namespace containing modules")
+	}
+
+	newId, err := client.UpdateModule(ctx, id, terrareg.ModuleUpdateModel{
+		ModuleModel: &terrareg.ModuleModel{GitTagFormat: "{version}"},
+		Namespace:   "example",
+		Name:        "network",
+		Provider:    "aws",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetModule(ctx, id); !errors.Is(err, terrareg.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for renamed module, got %v", err)
+	}
+	module, err = client.GetModule(ctx, newId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.GitTagFormat != "{version}" {
+		t.Errorf("unexpected git tag format %q", module.GitTagFormat)
+	}
+
+	if err := client.DeleteModule(ctx, newId); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteNamespace(ctx, "example"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListNamespaceModules(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	if _, err := client.ListNamespaceModules(ctx, "example"); !errors.Is(err, terrareg.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for namespace that does not exist, got %v", err)
+	}
+
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	modules, err := client.ListNamespaceModules(ctx, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 0 {
+		t.Errorf("expected no modules, got %v", modules)
+	}
+
+	// Create enough modules to require multiple pages
+	for i := 0; i < 120; i++ {
+		id := terrareg.ModuleProviderID{Namespace: "example", Name: fmt.Sprintf("module%03d", i), Provider: "aws"}
+		if _, err := client.CreateModule(ctx, id, terrareg.ModuleModel{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modules, err = client.ListNamespaceModules(ctx, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 120 {
+		t.Fatalf("expected 120 modules, got %d", len(modules))
+	}
+	for i, module := range modules {
+		if expected := fmt.Sprintf("example/module%03d/aws", i); module.String() != expected {
+			t.Errorf("expected module %d to be %q, got %q", i, expected, module)
+		}
+	}
+}
+
+func TestReadOnlyEndpoints(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := terrareg.CollectUnknownFields(context.Background())
+
+	gitProviders, err := client.GetGitProviders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gitProviders) != 3 || gitProviders[2].Name != "Gitlab" {
+		t.Errorf("unexpected git providers %v", gitProviders)
+	}
+
+	if err := client.DetectCapabilities(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if version := client.Capabilities().Version.String(); version != DefaultVersion {
+		t.Errorf("unexpected version %q", version)
+	}
+	if allowed := client.Capabilities().Config.AllowCustomGitUrlModuleProvider; allowed == nil || !*allowed {
+		t.Errorf("expected custom git URLs to be allowed")
+	}
+	if unknownFields := client.TakeUnknownFields(ctx); len(unknownFields) != 0 {
+		t.Errorf("unexpected unknown fields %v", unknownFields)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+
+	status, err := client.GetAuthenticationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Authenticated || !status.SiteAdmin {
+		t.Errorf("unexpected authentication status %+v", status)
+	}
+
+	unauthenticated, err := terrareg.NewClient(server.URL, terrareg.WithAdminToken("invalid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err = unauthenticated.GetAuthenticationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Authenticated {
+		t.Errorf("expected invalid token to be unauthenticated")
+	}
+	err = unauthenticated.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"})
+	if !errors.Is(err, terrareg.ErrInvalidAuth) {
+		t.Errorf("expected ErrInvalidAuth, got %v", err)
+	}
+}
+
+func TestScopedApiKeys(t *testing.T) {
+	server, admin := newTestServer(t)
+	server.UploadApiKey = "upload-key"
+	server.PublishApiKey = "publish-key"
+	ctx := context.Background()
+
+	id := terrareg.ModuleProviderID{Namespace: "example", Name: "vpc", Provider: "aws"}
+	if err := admin.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admin.CreateModule(ctx, id, terrareg.ModuleModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := terrareg.NewClient(
+		server.URL,
+		terrareg.WithUploadApiKey("upload-key"),
+		terrareg.WithPublishApiKey("publish-key"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadModuleVersion(ctx, id, "1.0.0", strings.NewReader("module archive")); err != nil {
+		t.Errorf("expected upload key to be accepted, got %v", err)
+	}
+	if err := client.PublishModuleVersion(ctx, id, "1.0.0"); err != nil {
+		t.Errorf("expected publish key to be accepted, got %v", err)
+	}
+	if err := client.DeleteModule(ctx, id); !errors.Is(err, terrareg.ErrInvalidAuth) {
+		t.Errorf("expected ErrInvalidAuth deleting module without admin token, got %v", err)
+	}
+
+	// Keys are only accepted for their own action
+	swapped, err := terrareg.NewClient(
+		server.URL,
+		terrareg.WithUploadApiKey("publish-key"),
+		terrareg.WithPublishApiKey("upload-key"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := swapped.UploadModuleVersion(ctx, id, "1.1.0", strings.NewReader("module archive")); !errors.Is(err, terrareg.ErrInvalidAuth) {
+		t.Errorf("expected ErrInvalidAuth uploading with publish key, got %v", err)
+	}
+	if err := swapped.PublishModuleVersion(ctx, id, "1.0.0"); !errors.Is(err, terrareg.ErrInvalidAuth) {
+		t.Errorf("expected ErrInvalidAuth publishing with upload key, got %v", err)
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+
+	if err := client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[int]error{
+		http.StatusUnauthorized:        terrareg.ErrInvalidAuth,
+		http.StatusForbidden:           terrareg.ErrUnauthorized,
+		http.StatusNotFound:            terrareg.ErrNotFound,
+		http.StatusInternalServerError: terrareg.ErrUnknownServerError,
+	}
+	for statusCode, expected := range tests {
+		server.InjectFault(Fault{Method: http.MethodGet, Path: "/v1/terrareg/namespaces/example", StatusCode: statusCode})
+		if _, err := client.GetNamespace(ctx, "example"); !errors.Is(err, expected) {
+			t.Errorf("%d: expected %v, got %v", statusCode, expected, err)
+		}
+		server.ClearFaults()
+	}
+
+	// Transient errors are retried by the client
+	server.InjectFault(Fault{Path: "/v1/terrareg/namespaces/example", StatusCode: http.StatusServiceUnavailable, Times: 2})
+	requests := server.Requests()
+	if _, err := client.GetNamespace(ctx, "example"); err != nil {
+		t.Errorf("expected request to be retried, got %v", err)
+	}
+	if made := server.Requests() - requests; made != 3 {
+		t.Errorf("expected 3 requests, got %d", made)
+	}
+}
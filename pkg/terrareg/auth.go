@@ -0,0 +1,50 @@
+package terrareg
+
+import (
+	"net/http"
+)
+
+// authScope identifies the type of credential required by an API endpoint.
+//
+// Terrareg provides separate API keys for uploading/importing and
+// publishing module versions, so that these can be used (e.g. in CI pipelines)
+// without requiring the admin token.
+type authScope int
+
+const (
+	authScopeAdmin authScope = iota
+	authScopeUpload
+	authScopePublish
+)
+
+const (
+	headerAdminApiKey   = "X-Terrareg-ApiKey"
+	headerUploadApiKey  = "X-Terrareg-Upload-Key"
+	headerPublishApiKey = "X-Terrareg-Publish-Key"
+)
+
+// HasAdminCredentials returns whether the client has an admin token or
+// bearer token, which are the only credentials that can be verified using
+// GetAuthenticationStatus. Upload and publish API keys cannot be verified
+// without uploading or publishing a module version.
+func (c *TerraregClient) HasAdminCredentials() bool {
+	return c.config.AdminToken != "" || c.config.BearerToken != ""
+}
+
+// setAuthHeaders sets the authentication headers on a request,
+// using the least privileged credential available for the scope.
+//
+// Requests that require an upload or publish key fall back to the
+// admin credentials if the respective key has not been provided.
+func (c *TerraregClient) setAuthHeaders(headers http.Header, scope authScope) {
+	switch {
+	case scope == authScopeUpload && c.config.UploadApiKey != "":
+		headers.Set(headerUploadApiKey, c.config.UploadApiKey)
+	case scope == authScopePublish && c.config.PublishApiKey != "":
+		headers.Set(headerPublishApiKey, c.config.PublishApiKey)
+	case c.config.AdminToken != "":
+		headers.Set(headerAdminApiKey, c.config.AdminToken)
+	case c.config.BearerToken != "":
+		headers.Set("Authorization", "Bearer "+c.config.BearerToken)
+	}
+}
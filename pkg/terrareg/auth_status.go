@@ -0,0 +1,56 @@
+package terrareg
+
+import (
+	"context"
+)
+
+// Namespace permission types
+const (
+	NamespacePermissionFull   = "FULL"
+	NamespacePermissionModify = "MODIFY"
+)
+
+// AuthenticationStatusModel is the authentication status and
+// permissions of the credentials used by the client.
+type AuthenticationStatusModel struct {
+	Authenticated bool `json:"authenticated"`
+	ReadAccess    bool `json:"read_access"`
+	SiteAdmin     bool `json:"site_admin"`
+	// Map of namespace name to permission type (FULL or MODIFY)
+	NamespacePermissions map[string]string `json:"namespace_permissions"`
+}
+
+// GetAuthenticationStatus returns the authentication status and
+// permissions of the admin or bearer token used by the client.
+//
+// Clients with only upload or publish API keys are reported as not
+// authenticated, so check HasAdminCredentials first.
+func (c *TerraregClient) GetAuthenticationStatus(ctx context.Context) (*AuthenticationStatusModel, error) {
+	url := c.getTerraregApiUrl("auth", "admin", "is_authenticated")
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	// Terrareg responds with 401 if the credentials are invalid
+	if res.StatusCode == 401 {
+		return &AuthenticationStatusModel{}, nil
+	}
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, newApiError(res, ErrUnknownError)
+	}
+
+	var data AuthenticationStatusModel
+	err = c.decodeResponse(ctx, res, &data, "authentication status")
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,167 @@
+package terrareg
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSetAuthHeaders(t *testing.T) {
+	admin := WithAdminToken("admin-token")
+	bearer := WithBearerToken("bearer-token")
+	upload := WithUploadApiKey("upload-key")
+	publish := WithPublishApiKey("publish-key")
+
+	adminHeaders := map[string]string{headerAdminApiKey: "admin-token"}
+	bearerHeaders := map[string]string{"Authorization": "Bearer bearer-token"}
+	uploadHeaders := map[string]string{headerUploadApiKey: "upload-key"}
+	publishHeaders := map[string]string{headerPublishApiKey: "publish-key"}
+	noHeaders := map[string]string{}
+
+	tests := []struct {
+		name     string
+		options  []Option
+		expected map[authScope]map[string]string
+	}{
+		{
+			name:    "no credentials",
+			options: nil,
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   noHeaders,
+				authScopeUpload:  noHeaders,
+				authScopePublish: noHeaders,
+			},
+		},
+		{
+			name:    "admin token",
+			options: []Option{admin},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   adminHeaders,
+				authScopeUpload:  adminHeaders,
+				authScopePublish: adminHeaders,
+			},
+		},
+		{
+			name:    "bearer token",
+			options: []Option{bearer},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   bearerHeaders,
+				authScopeUpload:  bearerHeaders,
+				authScopePublish: bearerHeaders,
+			},
+		},
+		{
+			// The admin token takes precedence over the bearer token
+			name:    "admin and bearer tokens",
+			options: []Option{admin, bearer},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   adminHeaders,
+				authScopeUpload:  adminHeaders,
+				authScopePublish: adminHeaders,
+			},
+		},
+		{
+			name:    "upload key",
+			options: []Option{upload},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   noHeaders,
+				authScopeUpload:  uploadHeaders,
+				authScopePublish: noHeaders,
+			},
+		},
+		{
+			name:    "publish key",
+			options: []Option{publish},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   noHeaders,
+				authScopeUpload:  noHeaders,
+				authScopePublish: publishHeaders,
+			},
+		},
+		{
+			name:    "upload and publish keys",
+			options: []Option{upload, publish},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   noHeaders,
+				authScopeUpload:  uploadHeaders,
+				authScopePublish: publishHeaders,
+			},
+		},
+		{
+			// Scoped keys are used in preference to the admin token
+			name:    "admin token and scoped keys",
+			options: []Option{admin, upload, publish},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   adminHeaders,
+				authScopeUpload:  uploadHeaders,
+				authScopePublish: publishHeaders,
+			},
+		},
+		{
+			name:    "admin token and upload key",
+			options: []Option{admin, upload},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   adminHeaders,
+				authScopeUpload:  uploadHeaders,
+				authScopePublish: adminHeaders,
+			},
+		},
+		{
+			name:    "bearer token and publish key",
+			options: []Option{bearer, publish},
+			expected: map[authScope]map[string]string{
+				authScopeAdmin:   bearerHeaders,
+				authScopeUpload:  bearerHeaders,
+				authScopePublish: publishHeaders,
+			},
+		},
+	}
+	scopeNames := map[authScope]string{
+		authScopeAdmin:   "admin",
+		authScopeUpload:  "upload",
+		authScopePublish: "publish",
+	}
+	for _, test := range tests {
+		client, err := NewClient("https://terrareg.example.com", test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for scope, expected := range test.expected {
+			headers := make(http.Header)
+			client.setAuthHeaders(headers, scope)
+
+			actual := make(map[string]string, len(headers))
+			for name := range headers {
+				actual[name] = headers.Get(name)
+			}
+			canonical := make(map[string]string, len(expected))
+			for name, value := range expected {
+				canonical[http.CanonicalHeaderKey(name)] = value
+			}
+			if !reflect.DeepEqual(actual, canonical) {
+				t.Errorf("%s, %s scope: expected headers %v, got %v", test.name, scopeNames[scope], canonical, actual)
+			}
+		}
+	}
+}
+
+func TestHasAdminCredentials(t *testing.T) {
+	tests := map[string]struct {
+		options  []Option
+		expected bool
+	}{
+		"no credentials":   {nil, false},
+		"admin token":      {[]Option{WithAdminToken("admin-token")}, true},
+		"bearer token":     {[]Option{WithBearerToken("bearer-token")}, true},
+		"scoped keys only": {[]Option{WithUploadApiKey("upload-key"), WithPublishApiKey("publish-key")}, false},
+	}
+	for name, test := range tests {
+		client, err := NewClient("https://terrareg.example.com", test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hasAdmin := client.HasAdminCredentials(); hasAdmin != test.expected {
+			t.Errorf("%s: expected %t, got %t", name, test.expected, hasAdmin)
+		}
+	}
+}
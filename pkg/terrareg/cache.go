@@ -0,0 +1,175 @@
+package terrareg
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the default time that read-mostly listings
+// (e.g. git providers) are cached by the client
+const DefaultCacheTTL = 5 * time.Minute
+
+// Keys of cached responses
+const (
+	cacheKeyGitProviders = "git_providers"
+	cacheKeyServerConfig = "config"
+	cacheKeyNamespaces   = "namespaces"
+)
+
+// responseCache caches the responses of read-mostly requests for the
+// lifetime of the provider, de-duplicating concurrent requests for the
+// same key, so that only a single request is made to Terrareg.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	timeout time.Duration
+	entries map[string]cacheEntry
+	calls   map[string]*cacheCall
+}
+
+// maxFetchTimeout bounds in-flight requests if no timeout is configured,
+// so that a stalled request does not run indefinitely once all of the
+// callers waiting for it have returned
+const maxFetchTimeout = DefaultRequestTimeout
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+	// Fields in the response that are not known to the client
+	unknownFields []string
+}
+
+// cacheCall is an in-flight request, shared by all callers
+type cacheCall struct {
+	done  chan struct{}
+	value any
+	err   error
+	// Fields in the response that are not known to the client,
+	// which are collected by each caller
+	unknownFields []string
+	// Set if the key is invalidated whilst the request is in-flight,
+	// in which case the response is not cached
+	invalidated bool
+}
+
+// newResponseCache creates a cache that stores values for the ttl, where
+// in-flight requests are cancelled after the timeout (or maxFetchTimeout,
+// if the timeout is 0).
+func newResponseCache(ttl time.Duration, timeout time.Duration) *responseCache {
+	if timeout <= 0 {
+		timeout = maxFetchTimeout
+	}
+	return &responseCache{
+		ttl:     ttl,
+		timeout: timeout,
+		entries: make(map[string]cacheEntry),
+		calls:   make(map[string]*cacheCall),
+	}
+}
+
+// get returns the cached value for the key, if it has not expired.
+// Otherwise, the value is obtained using fetch and cached on success.
+//
+// If a request for the key is already in-flight, the result of that
+// request is returned, rather than calling fetch again.
+// Values are shared between callers and must not be modified.
+//
+// fetch is called with a context that is not cancelled with ctx, as its
+// result is shared with other callers. If ctx is cancelled, get returns
+// ctx.Err() without waiting for fetch to complete. Unknown fields in the
+// response are collected by every caller that receives the value, using
+// the collector of its ctx (see CollectUnknownFields).
+func (r *responseCache) get(ctx context.Context, key string, fetch func(ctx context.Context) (any, error)) (any, error) {
+	r.mu.Lock()
+	if entry, ok := r.entries[key]; ok && time.Now().Before(entry.expires) {
+		r.mu.Unlock()
+		collectUnknownFields(ctx, entry.unknownFields)
+		return entry.value, nil
+	}
+	call, ok := r.calls[key]
+	if !ok {
+		call = &cacheCall{done: make(chan struct{})}
+		r.calls[key] = call
+		go r.fetch(ctx, key, call, fetch)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+		collectUnknownFields(ctx, call.unknownFields)
+		return call.value, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch makes the in-flight request for the key, caching the value on
+// success. The request uses the values of ctx (e.g. for logging), but is
+// only cancelled by the timeout, so that cancelling the caller that
+// started the request does not fail the other callers waiting for it.
+//
+// Unknown fields are collected for the request, rather than by the
+// caller that started it, so that they are passed to each caller.
+func (r *responseCache) fetch(ctx context.Context, key string, call *cacheCall, fetch func(ctx context.Context) (any, error)) {
+	collected := &unknownFields{}
+	ctx = context.WithValue(withoutCancel(ctx), unknownFieldsContextKey{}, collected)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	call.value, call.err = fetch(ctx)
+	for field := range collected.take() {
+		call.unknownFields = append(call.unknownFields, field)
+	}
+
+	r.mu.Lock()
+	if call.err == nil && !call.invalidated && r.ttl > 0 {
+		r.entries[key] = cacheEntry{value: call.value, expires: time.Now().Add(r.ttl), unknownFields: call.unknownFields}
+	}
+	if r.calls[key] == call {
+		delete(r.calls, key)
+	}
+	r.mu.Unlock()
+	close(call.done)
+}
+
+// invalidate removes the cached values for the keys, after a request
+// that modifies them. Requests already in-flight are not cached, and
+// subsequent requests are not coalesced with them.
+func (r *responseCache) invalidate(keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.entries, key)
+		if call, ok := r.calls[key]; ok {
+			call.invalidated = true
+			delete(r.calls, key)
+		}
+	}
+}
+
+// detachedContext is a context with the values of its parent, which is
+// never cancelled, equivalent to context.WithoutCancel (Go >= 1.21).
+type detachedContext struct {
+	parent context.Context
+}
+
+func withoutCancel(parent context.Context) context.Context {
+	return detachedContext{parent: parent}
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
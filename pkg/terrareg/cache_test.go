@@ -0,0 +1,277 @@
+package terrareg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCache_coalescesRequests(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 1, "name": "Github"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gitProviders, err := client.GetGitProviders(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(gitProviders) != 1 || gitProviders[0].Name != "Github" {
+				t.Errorf("unexpected git providers: %v", gitProviders)
+			}
+		}()
+	}
+	// Allow the requests to be coalesced before responding
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Subsequent requests are served from the cache
+	if _, err := client.GetGitProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := atomic.LoadInt32(&requests); count != 1 {
+		t.Errorf("expected 1 request, got %d", count)
+	}
+}
+
+func TestResponseCache_cancelledCaller(t *testing.T) {
+	var requests int32
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			close(received)
+		}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 1, "name": "Github"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first caller starts the request, then is cancelled
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error)
+	go func() {
+		_, err := client.GetGitProviders(cancelledCtx)
+		cancelledErr <- err
+	}()
+	<-received
+
+	// The second caller waits for the same request
+	liveErr := make(chan error)
+	go func() {
+		gitProviders, err := client.GetGitProviders(context.Background())
+		if err == nil && (len(gitProviders) != 1 || gitProviders[0].Name != "Github") {
+			err = fmt.Errorf("unexpected git providers: %v", gitProviders)
+		}
+		liveErr <- err
+	}()
+	// Allow the requests to be coalesced before cancelling
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled caller to return context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if err := <-liveErr; err != nil {
+		t.Errorf("expected live caller to succeed, got %v", err)
+	}
+
+	// The response is cached, despite the caller that started the request being cancelled
+	if _, err := client.GetGitProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if count := atomic.LoadInt32(&requests); count != 1 {
+		t.Errorf("expected 1 request, got %d", count)
+	}
+}
+
+func TestResponseCache_timeout(t *testing.T) {
+	cache := newResponseCache(time.Minute, 10*time.Millisecond)
+
+	_, err := cache.get(context.Background(), "key", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// Failed requests are not cached
+	value, err := cache.get(context.Background(), "key", func(ctx context.Context) (any, error) {
+		return "value", nil
+	})
+	if err != nil || value != "value" {
+		t.Errorf("expected value to be fetched again, got %v, %v", value, err)
+	}
+}
+
+func TestResponseCache_invalidate(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			_, _ = w.Write([]byte(`[{"name": "example", "display_name": "Example"}]`))
+		} else {
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListNamespaces(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.CreateNamespace(ctx, NamespaceConfigModel{Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListNamespaces(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two list requests, either side of the create request
+	if count := atomic.LoadInt32(&requests); count != 3 {
+		t.Errorf("expected 3 requests, got %d", count)
+	}
+}
+
+func TestResponseCache_errorsNotCached(t *testing.T) {
+	cache := newResponseCache(time.Minute, 0)
+	ctx := context.Background()
+
+	_, err := cache.get(ctx, "key", func(ctx context.Context) (any, error) {
+		return nil, errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	value, err := cache.get(ctx, "key", func(ctx context.Context) (any, error) {
+		return "value", nil
+	})
+	if err != nil || value != "value" {
+		t.Errorf("expected value to be fetched again, got %v, %v", value, err)
+	}
+}
+
+func TestResponseCache_disabled(t *testing.T) {
+	cache := newResponseCache(0, 0)
+	ctx := context.Background()
+
+	var fetches int
+	for i := 0; i < 2; i++ {
+		if _, err := cache.get(ctx, "key", func(ctx context.Context) (any, error) {
+			fetches++
+			return "value", nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("expected 2 fetches, got %d", fetches)
+	}
+}
+
+func TestResponseCache_defaultTimeout(t *testing.T) {
+	// Requests are bounded even if no timeout is configured
+	if cache := newResponseCache(time.Minute, 0); cache.timeout != maxFetchTimeout {
+		t.Errorf("expected timeout %s, got %s", maxFetchTimeout, cache.timeout)
+	}
+}
+
+func TestResponseCache_unknownFields(t *testing.T) {
+	cache := newResponseCache(time.Minute, 0)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (any, error) {
+		close(started)
+		<-release
+		collectUnknownFields(ctx, []string{"new_field"})
+		return "value", nil
+	}
+
+	// The first caller starts the request, then is cancelled
+	cancelledCollected := &unknownFields{}
+	cancelledCtx, cancel := context.WithCancel(context.WithValue(context.Background(), unknownFieldsContextKey{}, cancelledCollected))
+	cancelledErr := make(chan error)
+	go func() {
+		_, err := cache.get(cancelledCtx, "key", fetch)
+		cancelledErr <- err
+	}()
+	<-started
+
+	// The second caller waits for the same request
+	liveCollected := &unknownFields{}
+	liveCtx := context.WithValue(context.Background(), unknownFieldsContextKey{}, liveCollected)
+	liveErr := make(chan error)
+	go func() {
+		_, err := cache.get(liveCtx, "key", fetch)
+		liveErr <- err
+	}()
+	// Allow the requests to be coalesced before cancelling
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled caller to return context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := <-liveErr; err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown fields are collected by the waiting caller, despite the
+	// caller that started the request being cancelled
+	if fields := liveCollected.take(); !fields["new_field"] || len(fields) != 1 {
+		t.Errorf("expected unknown field new_field for waiting caller, got %v", fields)
+	}
+
+	// Unknown fields are also collected by callers served from the cache
+	cachedCollected := &unknownFields{}
+	cachedCtx := context.WithValue(context.Background(), unknownFieldsContextKey{}, cachedCollected)
+	if _, err := cache.get(cachedCtx, "key", fetch); err != nil {
+		t.Fatal(err)
+	}
+	if fields := cachedCollected.take(); !fields["new_field"] || len(fields) != 1 {
+		t.Errorf("expected unknown field new_field for cached caller, got %v", fields)
+	}
+}
@@ -0,0 +1,124 @@
+package terrareg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// Prefix of environment variables used by Terraform CLI to provide
+// credentials for a host, e.g. TF_TOKEN_registry_example_com
+const terraformTokenEnvPrefix = "TF_TOKEN_"
+
+// Name of credentials file written by "terraform login"
+const terraformCredentialsFile = "credentials.tfrc.json"
+
+// TerraformCLIToken finds a credential for the given host from the
+// Terraform CLI configuration, so that credentials created by
+// "terraform login" can be re-used.
+//
+// TF_TOKEN_<host> environment variables take precedence over the
+// credentials file. An empty string is returned if no credential is found.
+func TerraformCLIToken(host string) (string, error) {
+	hostname, err := svchost.ForComparison(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid host %q: %w", host, err)
+	}
+
+	if token := terraformTokenFromEnv(hostname); token != "" {
+		return token, nil
+	}
+
+	return terraformTokenFromCredentialsFile(hostname)
+}
+
+// terraformTokenFromEnv finds a TF_TOKEN_ environment variable for the host.
+//
+// Terraform encodes the host name in the variable name by replacing
+// periods with a single underscore and hyphens with double underscores.
+// Internationalised host names are encoded using punycode.
+func terraformTokenFromEnv(hostname svchost.Hostname) string {
+	for _, env := range os.Environ() {
+		name, value, found := strings.Cut(env, "=")
+		if !found || !strings.HasPrefix(name, terraformTokenEnvPrefix) || value == "" {
+			continue
+		}
+
+		rawHost := strings.TrimPrefix(name, terraformTokenEnvPrefix)
+		rawHost = strings.ReplaceAll(rawHost, "__", "-")
+		rawHost = strings.ReplaceAll(rawHost, "_", ".")
+
+		envHostname, err := svchost.ForComparison(rawHost)
+		if err != nil {
+			continue
+		}
+		if envHostname == hostname {
+			return value
+		}
+	}
+	return ""
+}
+
+// terraformConfigDir returns the directory used by Terraform
+// for CLI configuration, such as the credentials file.
+func terraformConfigDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", errors.New("APPDATA environment variable is not set")
+		}
+		return filepath.Join(appData, "terraform.d"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".terraform.d"), nil
+}
+
+// terraformTokenFromCredentialsFile finds a token for the host
+// in the credentials.tfrc.json file.
+func terraformTokenFromCredentialsFile(hostname svchost.Hostname) (string, error) {
+	configDir, err := terraformConfigDir()
+	if err != nil {
+		// No credentials can be found if the config
+		// directory cannot be determined
+		return "", nil
+	}
+
+	credentialsPath := filepath.Join(configDir, terraformCredentialsFile)
+	content, err := os.ReadFile(credentialsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s: %w", credentialsPath, err)
+	}
+
+	var credentials struct {
+		Credentials map[string]struct {
+			Token string `json:"token"`
+		} `json:"credentials"`
+	}
+	if err := json.Unmarshal(content, &credentials); err != nil {
+		return "", fmt.Errorf("unable to parse %s: %w", credentialsPath, err)
+	}
+
+	for host, credential := range credentials.Credentials {
+		fileHostname, err := svchost.ForComparison(host)
+		if err != nil {
+			continue
+		}
+		if fileHostname == hostname {
+			return credential.Token, nil
+		}
+	}
+	return "", nil
+}
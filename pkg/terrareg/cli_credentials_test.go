@@ -0,0 +1,66 @@
+package terrareg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTerraformCLIToken_environment(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TF_TOKEN_registry_example_com", "env-token")
+	t.Setenv("TF_TOKEN_my__registry_example_com", "hyphen-token")
+
+	tests := map[string]string{
+		"registry.example.com":      "env-token",
+		"REGISTRY.example.com":      "env-token",
+		"my-registry.example.com":   "hyphen-token",
+		"other.example.com":         "",
+		"registry.example.com:8443": "",
+	}
+	for host, expected := range tests {
+		token, err := TerraformCLIToken(host)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", host, err)
+		}
+		if token != expected {
+			t.Errorf("%s: expected token %q, got %q", host, expected, token)
+		}
+	}
+}
+
+func TestTerraformCLIToken_credentials_file(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("TF_TOKEN_env_example_com", "env-token")
+
+	configDir := filepath.Join(homeDir, ".terraform.d")
+	if err := os.Mkdir(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	credentials := `{
+  "credentials": {
+    "registry.example.com": {"token": "file-token"},
+    "env.example.com": {"token": "overridden-token"}
+  }
+}`
+	if err := os.WriteFile(filepath.Join(configDir, "credentials.tfrc.json"), []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"registry.example.com": "file-token",
+		// Environment variables take precedence over the credentials file
+		"env.example.com":   "env-token",
+		"other.example.com": "",
+	}
+	for host, expected := range tests {
+		token, err := TerraformCLIToken(host)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", host, err)
+		}
+		if token != expected {
+			t.Errorf("%s: expected token %q, got %q", host, expected, token)
+		}
+	}
+}
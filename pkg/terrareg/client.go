@@ -0,0 +1,350 @@
+package terrareg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// TerraregClient is a client for the Terrareg API.
+//
+// A client is safe for concurrent use and should be re-used, so that
+// connections, rate limits and cached responses are shared.
+type TerraregClient struct {
+	// Base URL of Terrareg, without a trailing slash
+	url    string
+	config clientConfig
+
+	// HTTP client, shared by all requests to make use of
+	// connection pooling
+	httpClient *http.Client
+
+	// Capabilities of the server, populated by DetectCapabilities
+	capabilities *Capabilities
+
+	// Base URLs of the Terrareg and module registry APIs, which are
+	// derived from the URL or obtained using DiscoverServices
+	terraregApiUrl string
+	modulesApiUrl  string
+	// Host used in module source addresses
+	registryHost string
+
+	// Limits applied to requests made to Terrareg
+	rateLimiter    *rateLimiter
+	inFlight       semaphore
+	namespaceLocks namespaceLocks
+
+	// Cache of read-mostly responses
+	cache *responseCache
+
+	// Fields in responses that are not known to the client,
+	// which have been returned by TakeUnknownFields
+	reportedFields unknownFields
+}
+
+// clientConfig contains the configuration of the client,
+// set using Option values passed to NewClient.
+type clientConfig struct {
+	// Admin authentication token
+	AdminToken string
+	// Token sent using a bearer Authorization header, such as a token
+	// obtained from the Terraform CLI credentials. This is only used
+	// if an API key has not been provided.
+	BearerToken string
+
+	// API keys used for uploading/importing and publishing module
+	// versions. If not provided, the admin API key is used.
+	UploadApiKey  string
+	PublishApiKey string
+
+	// Maximum number of times a failed request is retried
+	MaxRetries int
+	// Minimum and maximum time to wait between retries
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// Maximum number of idle connections kept open to Terrareg
+	MaxIdleConns int
+	// Timeout for each individual request attempt. 0 disables the timeout.
+	RequestTimeout time.Duration
+	// Disable requesting gzip compressed responses
+	DisableCompression bool
+
+	// PEM-encoded CA certificate(s) trusted in addition to the system CAs
+	CACertPEM string
+	// PEM-encoded client certificate and key for mutual TLS
+	ClientCertPEM string
+	ClientKeyPEM  string
+	// Disable verification of the Terrareg server certificate
+	InsecureSkipVerify bool
+	// URL of proxy to use for all requests. If not set, the proxy
+	// is obtained from the HTTP_PROXY/HTTPS_PROXY environment variables.
+	ProxyURL string
+
+	// User-Agent sent with all requests
+	UserAgent string
+	// Maximum number of requests per second. 0 disables rate limiting.
+	RateLimit float64
+	// Maximum number of requests that can be made at once, when the
+	// rate limit has not been reached.
+	RateLimitBurst int
+	// Maximum number of requests in-flight at once. 0 disables the limit.
+	MaxConcurrentRequests int
+
+	// Time to cache read-mostly listings, such as git providers.
+	// 0 disables caching, although concurrent requests are still
+	// de-duplicated.
+	CacheTTL time.Duration
+
+	// Return an error if a response contains fields that are not known
+	// to the client, rather than ignoring them.
+	StrictDecoding bool
+
+	// Additional static headers sent with all requests. These cannot
+	// override the headers set by the client, such as authentication.
+	Headers map[string]string
+
+	// Transport used to send requests, replacing the default transport.
+	// The TLS, proxy, compression and idle connection settings are
+	// not applied to a custom transport.
+	Transport http.RoundTripper
+}
+
+// DefaultUserAgent is the User-Agent used if one is not configured
+const DefaultUserAgent = "terraform-provider-terrareg"
+
+// Header containing a unique ID for each request, retained
+// across retries, to correlate requests with the Terrareg logs
+const headerRequestId = "X-Request-ID"
+
+func defaultClientConfig() clientConfig {
+	return clientConfig{
+		MaxRetries:     DefaultMaxRetries,
+		RetryWaitMin:   DefaultRetryWaitMin,
+		RetryWaitMax:   DefaultRetryWaitMax,
+		MaxIdleConns:   DefaultMaxIdleConns,
+		RequestTimeout: DefaultRequestTimeout,
+		UserAgent:      DefaultUserAgent,
+		CacheTTL:       DefaultCacheTTL,
+	}
+}
+
+// NewClient creates a client for the Terrareg instance at the URL,
+// which may include a sub-path if Terrareg is not hosted at the root
+// of the host (e.g. https://tools.example.com/registry).
+//
+// Without any options, the client makes unauthenticated requests,
+// using the default retry, timeout and caching behaviour.
+func NewClient(url string, opts ...Option) (*TerraregClient, error) {
+	url, err := NormalizeUrl(url)
+	if err != nil {
+		return nil, err
+	}
+
+	config := defaultClientConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative")
+	}
+	if config.RetryWaitMin > config.RetryWaitMax {
+		return nil, fmt.Errorf("minimum retry wait (%s) must not be greater than maximum retry wait (%s)", config.RetryWaitMin, config.RetryWaitMax)
+	}
+
+	if config.MaxIdleConns < 0 {
+		return nil, fmt.Errorf("max idle connections must not be negative")
+	}
+	if config.RequestTimeout < 0 {
+		return nil, fmt.Errorf("request timeout must not be negative")
+	}
+	if config.RateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative")
+	}
+	if config.RateLimitBurst < 0 {
+		return nil, fmt.Errorf("rate limit burst must not be negative")
+	}
+	if config.MaxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("max concurrent requests must not be negative")
+	}
+	if config.CacheTTL < 0 {
+		return nil, fmt.Errorf("cache TTL must not be negative")
+	}
+
+	httpClient, err := newHttpClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	registryHost, err := urlHost(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TerraregClient{
+		url:            url,
+		config:         config,
+		httpClient:     httpClient,
+		terraregApiUrl: url + "/v1/terrareg",
+		modulesApiUrl:  url + "/v1/modules",
+		registryHost:   registryHost,
+		rateLimiter:    newRateLimiter(config.RateLimit, config.RateLimitBurst),
+		inFlight:       newSemaphore(config.MaxConcurrentRequests),
+		cache:          newResponseCache(config.CacheTTL, config.RequestTimeout),
+	}, nil
+}
+
+// URL returns the base URL of Terrareg
+func (c *TerraregClient) URL() string {
+	return c.url
+}
+
+func (c *TerraregClient) getHeaders(contentType string, scope authScope, requestId string) http.Header {
+	headers := make(http.Header)
+	for name, value := range c.config.Headers {
+		headers.Set(name, value)
+	}
+
+	userAgent := c.config.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	headers.Set("User-Agent", userAgent)
+	headers.Set(headerRequestId, requestId)
+	headers.Set("Content-Type", contentType)
+	headers.Set("Accept", "application/json")
+	c.setAuthHeaders(headers, scope)
+
+	return headers
+}
+
+func (c *TerraregClient) getHttpClient() *http.Client {
+	return c.httpClient
+}
+
+// getTerraregApiUrl returns the URL of a Terrareg API endpoint,
+// escaping each of the path segments
+func (c *TerraregClient) getTerraregApiUrl(pathSegments ...string) string {
+	return fmt.Sprintf("%s/%s", c.terraregApiUrl, escapePathSegments(pathSegments...))
+}
+
+// getModuleProviderApiUrl returns the URL of a Terrareg API endpoint
+// for a module provider, escaping each of the path segments
+func (c *TerraregClient) getModuleProviderApiUrl(id ModuleProviderID, pathSegments ...string) string {
+	url := fmt.Sprintf("%s/modules/%s", c.terraregApiUrl, id.EscapedPath())
+	if len(pathSegments) > 0 {
+		url += "/" + escapePathSegments(pathSegments...)
+	}
+	return url
+}
+
+// getModulesApiUrl returns the URL of a module registry API endpoint,
+// escaping each of the path segments
+func (c *TerraregClient) getModulesApiUrl(pathSegments ...string) string {
+	return fmt.Sprintf("%s/%s", c.modulesApiUrl, escapePathSegments(pathSegments...))
+}
+
+func (c *TerraregClient) makeRequest(ctx context.Context, url string, requestMethod string, jsonData any) (*http.Response, error) {
+	return c.makeRequestWithAuth(ctx, url, requestMethod, jsonData, authScopeAdmin)
+}
+
+// makeRequestWithAuth makes a JSON request, authenticating using
+// the credentials for the given scope
+func (c *TerraregClient) makeRequestWithAuth(ctx context.Context, url string, requestMethod string, jsonData any, scope authScope) (*http.Response, error) {
+	body := new(bytes.Buffer)
+	if jsonData != nil {
+		err := json.NewEncoder(body).Encode(jsonData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.doRequest(ctx, url, requestMethod, body.Bytes(), "application/json", scope)
+}
+
+// doRequest sends the request to Terrareg, retrying on transient failures.
+func (c *TerraregClient) doRequest(ctx context.Context, url string, requestMethod string, bodyBytes []byte, contentType string, scope authScope) (*http.Response, error) {
+	httpClient := c.getHttpClient()
+	ctx = c.logContext(ctx)
+
+	requestId, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate request ID: %w", err)
+	}
+	ctx = tflog.SubsystemSetField(ctx, logSubsystem, "request_id", requestId)
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, url, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header = c.getHeaders(contentType, scope, requestId)
+
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		if err := c.inFlight.Acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		logRequest(ctx, req, bodyBytes, attempt)
+		start := time.Now()
+		httpRes, err := httpClient.Do(req)
+		// The response body is read in full when logging the response,
+		// so the request is no longer in-flight
+		logResponse(ctx, req, httpRes, err, time.Since(start).Milliseconds())
+		c.inFlight.Release()
+
+		if attempt >= c.config.MaxRetries || !c.shouldRetry(ctx, requestMethod, httpRes, err) {
+			return httpRes, err
+		}
+
+		wait := c.backoff(attempt, httpRes)
+		tflog.SubsystemInfo(ctx, logSubsystem, "Retrying request to Terrareg", map[string]interface{}{
+			"method":  requestMethod,
+			"url":     url,
+			"attempt": attempt + 1,
+			"wait_ms": wait.Milliseconds(),
+		})
+
+		// Discard the response of the failed attempt, so that
+		// the connection can be re-used
+		closeBody(httpRes)
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *TerraregClient) handleCommonStatusCode(res *http.Response) error {
+	if res.StatusCode == 404 {
+		return newApiError(res, ErrNotFound)
+	}
+	if res.StatusCode == 400 {
+		// Terrareg responds to requests for objects that do not exist
+		// with a 400 and a message, such as "Namespace does not exist"
+		apiErr := newApiError(res, ErrUnknownError)
+		if isNotFoundMessage(apiErr.Message) {
+			apiErr.Err = ErrNotFound
+		}
+		return apiErr
+	}
+	if res.StatusCode == 401 {
+		return newApiError(res, ErrInvalidAuth)
+	}
+	if res.StatusCode == 403 {
+		return newApiError(res, ErrUnauthorized)
+	}
+	if res.StatusCode >= 500 && res.StatusCode <= 503 {
+		return newApiError(res, ErrUnknownServerError)
+	}
+	return nil
+}
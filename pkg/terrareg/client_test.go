@@ -0,0 +1,65 @@
+package terrareg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestHeaders(t *testing.T) {
+	var requests []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Clone())
+		// Fail the first attempt, to ensure the request ID is retained on retry
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	userAgent := "terraform-provider-terrareg/1.0.0 terraform/1.6.0"
+	client, err := NewClient(
+		server.URL,
+		WithAdminToken("admin-key"),
+		WithRetryWait(time.Millisecond, time.Millisecond),
+		WithUserAgent(userAgent),
+		WithHeaders(map[string]string{
+			"X-Custom":          "custom-value",
+			"X-Terrareg-ApiKey": "overridden",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetGitProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	for _, headers := range requests {
+		if value := headers.Get("User-Agent"); value != userAgent {
+			t.Errorf("unexpected User-Agent %q", value)
+		}
+		if value := headers.Get("X-Custom"); value != "custom-value" {
+			t.Errorf("unexpected X-Custom %q", value)
+		}
+		if value := headers.Get(headerAdminApiKey); value != "admin-key" {
+			t.Errorf("unexpected %s %q", headerAdminApiKey, value)
+		}
+	}
+	requestId := requests[0].Get(headerRequestId)
+	if requestId == "" {
+		t.Error("expected request ID")
+	}
+	if retryRequestId := requests[1].Get(headerRequestId); retryRequestId != requestId {
+		t.Errorf("expected request ID %q to be retained on retry, got %q", requestId, retryRequestId)
+	}
+}
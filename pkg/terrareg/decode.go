@@ -0,0 +1,251 @@
+package terrareg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// unknownFieldsContextKey is the context key of the unknownFields
+// collecting the unknown fields of responses to requests made using the
+// context.
+type unknownFieldsContextKey struct{}
+
+// unknownFields records fields returned by Terrareg that are not
+// known to the client, which generally indicates that Terrareg has been
+// upgraded to a version that returns additional data.
+type unknownFields struct {
+	mu     sync.Mutex
+	fields map[string]bool
+}
+
+func (u *unknownFields) add(fields []string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.fields == nil {
+		u.fields = make(map[string]bool)
+	}
+	for _, field := range fields {
+		u.fields[field] = true
+	}
+}
+
+// take returns and removes the recorded fields.
+func (u *unknownFields) take() map[string]bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	fields := u.fields
+	u.fields = nil
+	return fields
+}
+
+// collectUnknownFields records the fields with the collector of the
+// context, if it was created using CollectUnknownFields.
+func collectUnknownFields(ctx context.Context, fields []string) {
+	if collected, ok := ctx.Value(unknownFieldsContextKey{}).(*unknownFields); ok {
+		collected.add(fields)
+	}
+}
+
+// CollectUnknownFields returns a context that collects the fields, found
+// in responses to requests made using the context, that are not known to
+// the client, so that they can be reported using TakeUnknownFields.
+//
+// Each operation should use its own context, so that unknown fields are
+// reported by the operation that received them, rather than by other
+// operations using the client concurrently.
+func CollectUnknownFields(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unknownFieldsContextKey{}, &unknownFields{})
+}
+
+// TakeUnknownFields returns the fields collected by the context, created
+// using CollectUnknownFields, that have not been returned by a previous
+// call for any context. Fields are in the form "<response>.<path>",
+// e.g. "namespace.new_attribute" or "git_providers[].new_attribute".
+func (c *TerraregClient) TakeUnknownFields(ctx context.Context) []string {
+	collected, ok := ctx.Value(unknownFieldsContextKey{}).(*unknownFields)
+	if !ok {
+		return nil
+	}
+
+	// Fields are only reported once by the client, regardless of the
+	// number of operations that receive them
+	reported := &c.reportedFields
+	reported.mu.Lock()
+	defer reported.mu.Unlock()
+	if reported.fields == nil {
+		reported.fields = make(map[string]bool)
+	}
+
+	var fields []string
+	for field := range collected.take() {
+		if !reported.fields[field] {
+			reported.fields[field] = true
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// decodeResponse decodes the JSON response body into v.
+//
+// Fields that are not known to the client are ignored and recorded, so
+// that they can be reported using TakeUnknownFields, if the context was
+// created using CollectUnknownFields, unless strict
+// decoding is enabled, in which case an error is returned.
+func (c *TerraregClient) decodeResponse(ctx context.Context, res *http.Response, v any, description string) error {
+	body, err := readAndDecode(res, v, description)
+	if err != nil {
+		return err
+	}
+
+	var raw any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return fmt.Errorf("unable to decode %s JSON from response body: %w", description, err)
+	}
+	fields := findUnknownFields(raw, reflect.TypeOf(v), strings.ReplaceAll(description, " ", "_"))
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if c.config.StrictDecoding {
+		return fmt.Errorf("unable to decode %s JSON from response body: %w: %s", description, ErrUnknownFields, strings.Join(fields, ", "))
+	}
+
+	tflog.SubsystemWarn(c.logContext(ctx), logSubsystem, "Ignoring unknown fields in response from Terrareg", map[string]interface{}{
+		"response": description,
+		"fields":   fields,
+	})
+	collectUnknownFields(ctx, fields)
+	return nil
+}
+
+// decodePartialResponse decodes the JSON response body into v, for
+// responses where the client only uses a subset of the fields, so
+// unknown fields are always ignored.
+func (c *TerraregClient) decodePartialResponse(res *http.Response, v any, description string) error {
+	_, err := readAndDecode(res, v, description)
+	return err
+}
+
+func readAndDecode(res *http.Response, v any, description string) ([]byte, error) {
+	if res.Body == nil {
+		return nil, ErrUnknownError
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s response body: %w", description, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("unable to decode %s JSON from response body: %w", description, err)
+	}
+	return body, nil
+}
+
+// findUnknownFields returns the paths of object keys in the decoded JSON
+// value that do not match a field of the type it was decoded into.
+func findUnknownFields(value any, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var fields []string
+	switch value := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Map:
+			for key, elem := range value {
+				fields = append(fields, findUnknownFields(elem, t.Elem(), path+"."+key)...)
+			}
+		case reflect.Struct:
+			known := jsonFields(t)
+			for key, elem := range value {
+				fieldType, ok := lookupJsonField(known, key)
+				if !ok {
+					fields = append(fields, path+"."+key)
+					continue
+				}
+				fields = append(fields, findUnknownFields(elem, fieldType, path+"."+key)...)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			// Report each unknown field once, regardless of the
+			// number of elements it appears in
+			seen := make(map[string]bool)
+			for _, elem := range value {
+				for _, field := range findUnknownFields(elem, t.Elem(), path+"[]") {
+					if !seen[field] {
+						seen[field] = true
+						fields = append(fields, field)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// jsonFields returns the JSON names of the fields of a struct type,
+// including those of embedded structs, mapped to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	embedded := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range jsonFields(fieldType) {
+				embedded[embeddedName] = embeddedType
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	// Fields of the struct take precedence over embedded fields
+	for name, fieldType := range embedded {
+		if _, ok := fields[name]; !ok {
+			fields[name] = fieldType
+		}
+	}
+	return fields
+}
+
+// lookupJsonField finds a field by its JSON name, matching
+// case-insensitively, as encoding/json does when decoding.
+func lookupJsonField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if fieldType, ok := fields[key]; ok {
+		return fieldType, true
+	}
+	for name, fieldType := range fields {
+		if strings.EqualFold(name, key) {
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,150 @@
+package terrareg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func newDecodeTestClient(t *testing.T, body string, strict bool) *TerraregClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, WithStrictDecoding(strict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestDecodeResponse_unknownFields(t *testing.T) {
+	client := newDecodeTestClient(t, `{"display_name": "Example", "trusted": true, "is_auto_verified": false, "new_field": 1, "other": {"a": 1}}`, false)
+	ctx := CollectUnknownFields(context.Background())
+
+	namespace, err := client.GetNamespace(ctx, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if namespace.DisplayName != "Example" || !namespace.Trusted {
+		t.Errorf("unexpected namespace: %+v", namespace)
+	}
+
+	expected := []string{"namespace.new_field", "namespace.other"}
+	if fields := client.TakeUnknownFields(ctx); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected unknown fields %v, got %v", expected, fields)
+	}
+
+	// Fields are only returned once, including for other contexts
+	if _, err := client.GetNamespace(ctx, "example"); err != nil {
+		t.Fatal(err)
+	}
+	if fields := client.TakeUnknownFields(ctx); len(fields) != 0 {
+		t.Errorf("expected no new unknown fields, got %v", fields)
+	}
+	otherCtx := CollectUnknownFields(context.Background())
+	if _, err := client.GetNamespace(otherCtx, "example"); err != nil {
+		t.Fatal(err)
+	}
+	if fields := client.TakeUnknownFields(otherCtx); len(fields) != 0 {
+		t.Errorf("expected no new unknown fields, got %v", fields)
+	}
+}
+
+func TestDecodeResponse_unknownFieldsPerContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/terrareg/namespaces/one":
+			_, _ = w.Write([]byte(`{"display_name": "One", "one_field": 1}`))
+		case "/v1/terrareg/namespaces/two":
+			_, _ = w.Write([]byte(`{"display_name": "Two", "two_field": 2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each context only collects the fields of its own responses,
+	// when used concurrently
+	expected := map[string][]string{
+		"one": {"namespace.one_field"},
+		"two": {"namespace.two_field"},
+	}
+	var wg sync.WaitGroup
+	for name := range expected {
+		name := name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := CollectUnknownFields(context.Background())
+			if _, err := client.GetNamespace(ctx, name); err != nil {
+				t.Error(err)
+				return
+			}
+			if fields := client.TakeUnknownFields(ctx); !reflect.DeepEqual(fields, expected[name]) {
+				t.Errorf("expected unknown fields %v for %s, got %v", expected[name], name, fields)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Fields are not collected without a collecting context
+	if _, err := client.GetNamespace(context.Background(), "one"); err != nil {
+		t.Fatal(err)
+	}
+	if fields := client.TakeUnknownFields(context.Background()); fields != nil {
+		t.Errorf("expected no unknown fields, got %v", fields)
+	}
+}
+
+func TestDecodeResponse_strict(t *testing.T) {
+	client := newDecodeTestClient(t, `{"display_name": "Example", "new_field": 1}`, true)
+
+	_, err := client.GetNamespace(context.Background(), "example")
+	if !errors.Is(err, ErrUnknownFields) {
+		t.Errorf("expected ErrUnknownFields, got %v", err)
+	}
+}
+
+func TestFindUnknownFields(t *testing.T) {
+	type embedded struct {
+		Embedded string `json:"embedded"`
+	}
+	type model struct {
+		*embedded
+		Name     string             `json:"name"`
+		Children []struct{ ID int } `json:"children"`
+		Labels   map[string]string  `json:"labels"`
+		Ignored  string             `json:"-"`
+	}
+
+	value := map[string]any{
+		"embedded": "value",
+		"NAME":     "case-insensitive",
+		"Ignored":  "not decoded",
+		"children": []any{
+			map[string]any{"id": 1, "extra": true},
+			map[string]any{"ID": 2, "extra": true},
+		},
+		"labels": map[string]any{"key": "value"},
+	}
+
+	expected := []string{"model.Ignored", "model.children[].extra"}
+	fields := findUnknownFields(value, reflect.TypeOf(&model{}), "model")
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
@@ -0,0 +1,119 @@
+package terrareg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Path of the Terraform service discovery document, relative to the host
+const discoveryPath = "/.well-known/terraform.json"
+
+// Service discovery keys
+const (
+	serviceModulesV1  = "modules.v1"
+	serviceTerraregV1 = "terrareg.v1"
+)
+
+// DiscoverServices obtains the API endpoints from the Terraform service
+// discovery document of the configured host, replacing the default
+// API URLs derived from the Terrareg URL.
+//
+// The Terrareg API is obtained from the "terrareg.v1" service, if provided.
+// Otherwise, it is assumed to be alongside the "modules.v1" API
+// (e.g. /v1/modules/ -> /v1/terrareg/).
+func (c *TerraregClient) DiscoverServices(ctx context.Context) error {
+	baseUrl, err := url.Parse(c.url)
+	if err != nil {
+		return err
+	}
+	discoveryUrl := &url.URL{
+		Scheme: baseUrl.Scheme,
+		Host:   baseUrl.Host,
+		Path:   discoveryPath,
+	}
+
+	res, err := c.makeRequest(ctx, discoveryUrl.String(), "GET", nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	dec := json.NewDecoder(res.Body)
+
+	// Services may be URLs or objects (e.g. login.v1),
+	// so only decode the values as strings when used
+	var services map[string]json.RawMessage
+	err = dec.Decode(&services)
+	if err != nil {
+		return fmt.Errorf("unable to decode service discovery JSON from response body: %w", err)
+	}
+
+	modulesUrl, err := resolveService(discoveryUrl, services, serviceModulesV1)
+	if err != nil {
+		return err
+	}
+	if modulesUrl == nil {
+		return fmt.Errorf("service discovery document at %s does not provide %s", discoveryUrl, serviceModulesV1)
+	}
+
+	terraregUrl, err := resolveService(discoveryUrl, services, serviceTerraregV1)
+	if err != nil {
+		return err
+	}
+	if terraregUrl == nil {
+		terraregUrl = modulesUrl.ResolveReference(&url.URL{Path: "../terrareg/"})
+	}
+
+	c.modulesApiUrl = strings.TrimRight(modulesUrl.String(), "/")
+	c.terraregApiUrl = strings.TrimRight(terraregUrl.String(), "/")
+	c.registryHost = discoveryUrl.Host
+	return nil
+}
+
+// resolveService resolves the URL of a service from the discovery document,
+// relative to the document URL. Returns nil if the service is not present.
+func resolveService(discoveryUrl *url.URL, services map[string]json.RawMessage, service string) (*url.URL, error) {
+	rawValue, ok := services[service]
+	if !ok {
+		return nil, nil
+	}
+	var value string
+	if err := json.Unmarshal(rawValue, &value); err != nil {
+		return nil, fmt.Errorf("invalid value for %s in service discovery document: %w", service, err)
+	}
+	serviceUrl, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL for %s in service discovery document: %w", service, err)
+	}
+	serviceUrl = discoveryUrl.ResolveReference(serviceUrl)
+
+	// Ensure the path is treated as a directory when
+	// resolving relative URLs from it
+	if !strings.HasSuffix(serviceUrl.Path, "/") {
+		serviceUrl.Path += "/"
+	}
+	return serviceUrl, nil
+}
+
+// RegistryHost returns the host used in module source addresses
+// for modules hosted in Terrareg.
+func (c *TerraregClient) RegistryHost() string {
+	return c.registryHost
+}
+
+// ModuleSource returns the source address used to reference
+// a module provider from Terraform.
+func (c *TerraregClient) ModuleSource(id ModuleProviderID) string {
+	return fmt.Sprintf("%s/%s", c.registryHost, id)
+}
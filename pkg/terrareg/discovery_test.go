@@ -0,0 +1,70 @@
+package terrareg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newDiscoveryTestClient(t *testing.T, document string) (*TerraregClient, string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != discoveryPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(document))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL + "/ignored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestDiscoverServices(t *testing.T) {
+	client, host := newDiscoveryTestClient(t, `{"modules.v1": "/registry/v1/modules/", "login.v1": {"client": "terraform-cli"}}`)
+
+	if err := client.DiscoverServices(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "http://" + host + "/registry/v1/terrareg/namespaces"
+	if url := client.getTerraregApiUrl("namespaces"); url != expected {
+		t.Errorf("expected %q, got %q", expected, url)
+	}
+	expected = "http://" + host + "/registry/v1/modules/example"
+	if url := client.getModulesApiUrl("example"); url != expected {
+		t.Errorf("expected %q, got %q", expected, url)
+	}
+	if source := client.ModuleSource(ModuleProviderID{Namespace: "example", Name: "name", Provider: "aws"}); source != host+"/example/name/aws" {
+		t.Errorf("unexpected module source %q", source)
+	}
+}
+
+func TestDiscoverServices_terraregService(t *testing.T) {
+	client, _ := newDiscoveryTestClient(t, `{"modules.v1": "/v1/modules/", "terrareg.v1": "https://api.example.com/terrareg"}`)
+
+	if err := client.DiscoverServices(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "https://api.example.com/terrareg/namespaces"
+	if url := client.getTerraregApiUrl("namespaces"); url != expected {
+		t.Errorf("expected %q, got %q", expected, url)
+	}
+}
+
+func TestDiscoverServices_missingModules(t *testing.T) {
+	client, _ := newDiscoveryTestClient(t, `{"providers.v1": "/v1/providers/"}`)
+
+	if err := client.DiscoverServices(context.Background()); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,24 @@
+// Package terrareg is a client for the API of Terrareg
+// (https://github.com/MatthewJohn/terrareg), a private Terraform
+// module registry.
+//
+// A client is created using NewClient and configured using Option values:
+//
+//	client, err := terrareg.NewClient(
+//		"https://terrareg.example.com",
+//		terrareg.WithAdminToken(os.Getenv("TERRAREG_ADMIN_TOKEN")),
+//	)
+//
+// All methods accept a context, which is used to cancel requests.
+// Failed requests are retried for connection errors and transient server
+// errors, and errors returned by Terrareg are returned as an *APIError,
+// which wraps one of the package errors, such as ErrNotFound:
+//
+//	namespace, err := client.GetNamespace(ctx, "example")
+//	if errors.Is(err, terrareg.ErrNotFound) {
+//		// Namespace does not exist
+//	}
+//
+// This package is used by the Terrareg Terraform provider, and follows the
+// versioning of the provider.
+package terrareg
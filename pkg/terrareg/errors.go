@@ -0,0 +1,100 @@
+package terrareg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Errors returned by the client, which may be wrapped in an APIError.
+// Use errors.Is to check for them.
+var ErrNotFound = errors.New("Not found")
+var ErrInvalidAuth = errors.New("Invalid Authentication")
+var ErrUnauthorized = errors.New("Unauthorized")
+var ErrUnknownServerError = errors.New("Unknown Server error")
+var ErrUnknownError = errors.New("Unknown HTTP Response")
+
+// ErrUnknownFields is returned when a response contains fields that are
+// not known to the client and strict decoding is enabled.
+var ErrUnknownFields = errors.New("Unknown fields in response")
+
+// Maximum length of a raw response body included in an APIError,
+// when Terrareg did not return a JSON error message.
+const maxErrorBodyLength = 512
+
+// APIError describes an unsuccessful response from Terrareg.
+//
+// It wraps one of the package error values (e.g. ErrNotFound),
+// so can be checked using errors.Is.
+type APIError struct {
+	// Type of error, one of the package error values
+	Err error
+
+	Method     string
+	URL        string
+	StatusCode int
+
+	// Error message returned by Terrareg, if available
+	Message string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s: %s %s returned HTTP %d", e.Err, e.Method, e.URL, e.StatusCode)
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newApiError creates an APIError from the response, reading
+// the error message from the response body.
+func newApiError(res *http.Response, err error) *APIError {
+	apiErr := &APIError{
+		Err:        err,
+		StatusCode: res.StatusCode,
+	}
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.URL = res.Request.URL.String()
+	}
+
+	if res.Body == nil {
+		return apiErr
+	}
+	body, readErr := io.ReadAll(res.Body)
+	if readErr != nil {
+		return apiErr
+	}
+
+	// Terrareg returns errors in the form {"status": "Error", "message": "..."}
+	var errorResponse struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errorResponse) == nil && errorResponse.Message != "" {
+		apiErr.Message = errorResponse.Message
+		return apiErr
+	}
+
+	// Otherwise, fallback to the raw response body
+	message := strings.TrimSpace(string(body))
+	if len(message) > maxErrorBodyLength {
+		message = message[:maxErrorBodyLength] + "..."
+	}
+	apiErr.Message = message
+	return apiErr
+}
+
+// isNotFoundMessage returns whether a Terrareg error message indicates
+// that the requested object (or one of its parents) does not exist,
+// e.g. "Namespace does not exist", ignoring case and trailing punctuation.
+func isNotFoundMessage(message string) bool {
+	message = strings.TrimRight(strings.TrimSpace(message), ".!")
+	return strings.HasSuffix(strings.ToLower(message), "does not exist")
+}
@@ -0,0 +1,177 @@
+package terrareg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newErrorTestResponse(statusCode int, body string) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, "https://terrareg.example.com/v1/terrareg/namespaces", nil)
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestNewApiError(t *testing.T) {
+	longBody := strings.Repeat("a", maxErrorBodyLength+100)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"JSON message", `{"status": "Error", "message": "Namespace already exists"}`, "Namespace already exists"},
+		{"JSON without message", `{"status": "Error"}`, `{"status": "Error"}`},
+		{"non-JSON body", "<html><body>Bad Gateway</body></html>\n", "<html><body>Bad Gateway</body></html>"},
+		{"long non-JSON body", longBody, longBody[:maxErrorBodyLength] + "..."},
+		{"empty body", "", ""},
+	}
+	for _, test := range tests {
+		apiErr := newApiError(newErrorTestResponse(http.StatusBadRequest, test.body), ErrUnknownError)
+		if apiErr.Message != test.expected {
+			t.Errorf("%s: expected message %q, got %q", test.name, test.expected, apiErr.Message)
+		}
+		if apiErr.Method != http.MethodPost || apiErr.URL != "https://terrareg.example.com/v1/terrareg/namespaces" || apiErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: unexpected request details %s %s %d", test.name, apiErr.Method, apiErr.URL, apiErr.StatusCode)
+		}
+	}
+
+	// Responses without a body or request are handled
+	apiErr := newApiError(&http.Response{StatusCode: http.StatusInternalServerError}, ErrUnknownServerError)
+	if apiErr.Message != "" || apiErr.Method != "" || apiErr.URL != "" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
+
+func TestApiError_Error(t *testing.T) {
+	apiErr := newApiError(newErrorTestResponse(http.StatusForbidden, `{"message": "Permission denied"}`), ErrUnauthorized)
+	expected := "Unauthorized: POST https://terrareg.example.com/v1/terrareg/namespaces returned HTTP 403: Permission denied"
+	if apiErr.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, apiErr.Error())
+	}
+
+	apiErr = newApiError(newErrorTestResponse(http.StatusForbidden, ""), ErrUnauthorized)
+	expected = "Unauthorized: POST https://terrareg.example.com/v1/terrareg/namespaces returned HTTP 403"
+	if apiErr.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, apiErr.Error())
+	}
+}
+
+func TestApiError_unwrap(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrInvalidAuth, ErrUnauthorized, ErrUnknownServerError, ErrUnknownError}
+	for _, sentinel := range sentinels {
+		// Errors may be further wrapped by callers
+		err := fmt.Errorf("unable to create namespace: %w", newApiError(newErrorTestResponse(http.StatusBadRequest, ""), sentinel))
+
+		if !errors.Is(err, sentinel) {
+			t.Errorf("expected errors.Is to match %v", sentinel)
+		}
+		for _, other := range sentinels {
+			if other != sentinel && errors.Is(err, other) {
+				t.Errorf("expected errors.Is not to match %v for %v", other, sentinel)
+			}
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected errors.As to find APIError for %v", sentinel)
+		}
+		if apiErr.Err != sentinel || apiErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("unexpected APIError %+v", apiErr)
+		}
+	}
+}
+
+func TestHandleCommonStatusCode(t *testing.T) {
+	client, err := NewClient("https://terrareg.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[int]error{
+		http.StatusOK:                  nil,
+		http.StatusBadRequest:          ErrUnknownError,
+		http.StatusUnauthorized:        ErrInvalidAuth,
+		http.StatusForbidden:           ErrUnauthorized,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusInternalServerError: ErrUnknownServerError,
+		http.StatusServiceUnavailable:  ErrUnknownServerError,
+	}
+	for statusCode, expected := range tests {
+		err := client.handleCommonStatusCode(newErrorTestResponse(statusCode, `{"message": "Error"}`))
+		if expected == nil {
+			if err != nil {
+				t.Errorf("%d: unexpected error %v", statusCode, err)
+			}
+			continue
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, expected) {
+			t.Errorf("%d: expected APIError wrapping %v, got %v", statusCode, expected, err)
+			continue
+		}
+		if apiErr.Message != "Error" {
+			t.Errorf("%d: unexpected message %q", statusCode, apiErr.Message)
+		}
+	}
+}
+
+func TestIsNotFoundMessage(t *testing.T) {
+	tests := map[string]bool{
+		"Namespace does not exist":            true,
+		"Module provider does not exist":      true,
+		"Namespace does not exist.":           true,
+		"Namespace does not exist!":           true,
+		"Namespace does not exist. \n":        true,
+		"NAMESPACE DOES NOT EXIST":            true,
+		"namespace Does Not Exist.":           true,
+		"":                                    false,
+		"Namespace already exists":            false,
+		"Invalid namespace name":              false,
+		"Namespace does not exist yet, retry": false,
+		"does not exist: invalid path":        false,
+	}
+	for message, expected := range tests {
+		if notFound := isNotFoundMessage(message); notFound != expected {
+			t.Errorf("%q: expected %t, got %t", message, expected, notFound)
+		}
+	}
+}
+
+func TestHandleCommonStatusCode_badRequest(t *testing.T) {
+	client, err := NewClient("https://terrareg.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		body     string
+		expected error
+	}{
+		// Terrareg responds with 400 for objects that do not exist
+		{`{"status": "Error", "message": "Namespace does not exist"}`, ErrNotFound},
+		{`{"status": "Error", "message": "Module provider does not exist."}`, ErrNotFound},
+		{"Namespace does not exist\n", ErrNotFound},
+		// Other 400 responses are not mapped to ErrNotFound
+		{`{"status": "Error", "message": "Invalid namespace name"}`, ErrUnknownError},
+		{`{"status": "Error"}`, ErrUnknownError},
+		{"", ErrUnknownError},
+	}
+	for _, test := range tests {
+		err := client.handleCommonStatusCode(newErrorTestResponse(http.StatusBadRequest, test.body))
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.body, test.expected, err)
+		}
+		if test.expected != ErrNotFound && errors.Is(err, ErrNotFound) {
+			t.Errorf("%q: unexpected ErrNotFound", test.body)
+		}
+	}
+}
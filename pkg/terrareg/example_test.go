@@ -0,0 +1,161 @@
+package terrareg_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dockstudios/terraform-provider-terrareg/pkg/terrareg"
+)
+
+func ExampleNewClient() {
+	client, err := terrareg.NewClient(
+		"https://terrareg.example.com",
+		terrareg.WithAdminToken(os.Getenv("TERRAREG_ADMIN_TOKEN")),
+		terrareg.WithMaxRetries(2),
+		terrareg.WithRequestTimeout(30*time.Second),
+		terrareg.WithUserAgent("release-bot/1.0"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(client.URL())
+	// Output: https://terrareg.example.com
+}
+
+func ExampleNewClient_terraformCLICredentials() {
+	// Use the credentials created by "terraform login"
+	token, err := terrareg.TerraformCLIToken("terrareg.example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := terrareg.NewClient(
+		"https://terrareg.example.com",
+		terrareg.WithBearerToken(token),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_ = client
+}
+
+func ExampleTerraregClient_GetNamespace() {
+	client, err := terrareg.NewClient("https://terrareg.example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	namespace, err := client.GetNamespace(context.Background(), "example")
+	if errors.Is(err, terrareg.ErrNotFound) {
+		fmt.Println("namespace does not exist")
+		return
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(namespace.DisplayName, namespace.Trusted)
+}
+
+func ExampleTerraregClient_CreateModule() {
+	client, err := terrareg.NewClient(
+		"https://terrareg.example.com",
+		terrareg.WithAdminToken(os.Getenv("TERRAREG_ADMIN_TOKEN")),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	err = client.CreateNamespace(ctx, terrareg.NamespaceConfigModel{
+		Name:        "example",
+		DisplayName: "Example",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	id, err := client.CreateModule(ctx, terrareg.ModuleProviderID{
+		Namespace: "example",
+		Name:      "vpc",
+		Provider:  "aws",
+	}, terrareg.ModuleModel{
+		RepoCloneUrlTemplate: "ssh://git@git.example.com/{namespace}/{module}-{provider}.git",
+		GitTagFormat:         "v{version}",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Import the first version of the module from its git repository
+	err = client.ImportModuleVersion(ctx, id, terrareg.ModuleVersionImportModel{
+		Version: "1.0.0",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(id)
+}
+
+func ExampleAPIError() {
+	client, err := terrareg.NewClient("https://terrareg.example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = client.DeleteNamespace(context.Background(), "example")
+
+	// APIError provides the details of the failed request
+	var apiErr *terrareg.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Println(apiErr.StatusCode, apiErr.Message)
+	}
+}
+
+func ExampleTerraregClient_UploadModuleVersion() {
+	client, err := terrareg.NewClient(
+		"https://terrareg.example.com",
+		terrareg.WithUploadApiKey(os.Getenv("TERRAREG_UPLOAD_API_KEY")),
+		terrareg.WithPublishApiKey(os.Getenv("TERRAREG_PUBLISH_API_KEY")),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	id, err := terrareg.ParseModuleProviderID("example/vpc/aws")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	archive, err := os.Open("module.zip")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer archive.Close()
+
+	if err := client.UploadModuleVersion(ctx, id, "1.0.0", archive); err != nil {
+		log.Fatal(err)
+	}
+	if err := client.PublishModuleVersion(ctx, id, "1.0.0"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleTerraregClient_ModuleSource() {
+	client, err := terrareg.NewClient("https://terrareg.example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source := client.ModuleSource(terrareg.ModuleProviderID{
+		Namespace: "example",
+		Name:      "vpc",
+		Provider:  "aws",
+	})
+	fmt.Printf("source = %q\n", source)
+	// Output: source = "terrareg.example.com/example/vpc/aws"
+}
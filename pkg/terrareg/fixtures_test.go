@@ -0,0 +1,338 @@
+package terrareg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dockstudios/terraform-provider-terrareg/internal/terraregtest"
+)
+
+// Environment variable that, when set, records the fixture files used by the
+// fixture tests, rather than replaying them. Requests are recorded against
+// the Terrareg instance at TERRAREG_URL (using TERRAREG_API_KEY and, for
+// fixtures using scoped keys, TERRAREG_UPLOAD_API_KEY and
+// TERRAREG_PUBLISH_API_KEY), or against the fake Terrareg server if
+// TERRAREG_URL is not set.
+//
+// Fixture clients decode responses strictly, so that fields returned by
+// Terrareg that are not known to the client fail the tests, and the tests
+// assert the decoded responses. Fixture files must be recorded against a
+// Terrareg instance to detect differences between the client and Terrareg;
+// those recorded against the fake server (reporting terraregtest.DefaultVersion)
+// only assert the requests made by the client.
+const recordFixturesEnvVar = "TERRAREG_RECORD_FIXTURES"
+
+// Credentials used by fixture clients when replaying fixture files
+const (
+	fixtureAdminToken    = "fixture-admin-token"
+	fixtureUploadApiKey  = "fixture-upload-key"
+	fixturePublishApiKey = "fixture-publish-key"
+)
+
+// newFixtureClient creates a client that replays the requests and responses
+// in the named fixture file, failing the test if the client's requests,
+// including the credential sent with each request, do not match the
+// recorded requests.
+//
+// If scopedKeys is set, the client uses upload and publish API keys,
+// rather than the admin token, for importing, uploading and publishing.
+func newFixtureClient(t *testing.T, name string, scopedKeys bool) *TerraregClient {
+	t.Helper()
+
+	url := "http://terrareg.test"
+	adminToken := fixtureAdminToken
+	uploadApiKey := fixtureUploadApiKey
+	publishApiKey := fixturePublishApiKey
+	mode := terraregtest.ModeReplay
+
+	if os.Getenv(recordFixturesEnvVar) != "" {
+		mode = terraregtest.ModeRecord
+		url = os.Getenv("TERRAREG_URL")
+		if url == "" {
+			server := terraregtest.NewServer(fixtureAdminToken)
+			server.UploadApiKey = fixtureUploadApiKey
+			server.PublishApiKey = fixturePublishApiKey
+			t.Cleanup(server.Close)
+			url = server.URL
+		} else {
+			adminToken = os.Getenv("TERRAREG_API_KEY")
+			uploadApiKey = os.Getenv("TERRAREG_UPLOAD_API_KEY")
+			publishApiKey = os.Getenv("TERRAREG_PUBLISH_API_KEY")
+			if scopedKeys && (uploadApiKey == "" || publishApiKey == "") {
+				t.Fatalf("Recording %s requires TERRAREG_UPLOAD_API_KEY and TERRAREG_PUBLISH_API_KEY", name)
+			}
+		}
+	}
+
+	recorder, err := terraregtest.NewRecorder(filepath.Join("testdata", "fixtures", name+".json"), mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := recorder.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	options := []Option{
+		WithAdminToken(adminToken),
+		WithTransport(recorder),
+		WithUserAgent("terraform-provider-terrareg/fixtures"),
+		WithMaxRetries(0),
+		WithCacheTTL(0),
+		WithStrictDecoding(true),
+	}
+	recorder.RedactCredential(adminToken, "<admin>")
+	if scopedKeys {
+		options = append(options, WithUploadApiKey(uploadApiKey), WithPublishApiKey(publishApiKey))
+		recorder.RedactCredential(uploadApiKey, "<upload>")
+		recorder.RedactCredential(publishApiKey, "<publish>")
+	}
+
+	client, err := NewClient(url, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestFixtureNamespaces(t *testing.T) {
+	client := newFixtureClient(t, "namespaces", false)
+	ctx := context.Background()
+
+	err := client.CreateNamespace(ctx, NamespaceConfigModel{Name: "fixture-namespace", DisplayName: "Fixture Namespace"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespaces, err := client.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, namespace := range namespaces {
+		found = found || (namespace.Name == "fixture-namespace" && namespace.DisplayName == "Fixture Namespace")
+	}
+	if !found {
+		t.Errorf("created namespace not listed in %v", namespaces)
+	}
+
+	namespace, err := client.GetNamespace(ctx, "fixture-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NamespaceModel{DisplayName: "Fixture Namespace", IsAutoVerified: false, Trusted: false}
+	if *namespace != expected {
+		t.Errorf("expected namespace %+v, got %+v", expected, *namespace)
+	}
+
+	err = client.UpdateNamespace(ctx, "fixture-namespace", NamespaceConfigModel{Name: "fixture-namespace-renamed", DisplayName: "Renamed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	namespace, err = client.GetNamespace(ctx, "fixture-namespace-renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if namespace.DisplayName != "Renamed" {
+		t.Errorf("unexpected display name %q", namespace.DisplayName)
+	}
+
+	if err := client.DeleteNamespace(ctx, "fixture-namespace-renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetNamespace(ctx, "fixture-namespace-renamed"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for deleted namespace, got %v", err)
+	}
+}
+
+func TestFixtureModules(t *testing.T) {
+	client := newFixtureClient(t, "modules", false)
+	ctx := context.Background()
+
+	if err := client.CreateNamespace(ctx, NamespaceConfigModel{Name: "fixture-modules"}); err != nil {
+		t.Fatal(err)
+	}
+
+	id := ModuleProviderID{Namespace: "fixture-modules", Name: "vpc", Provider: "aws"}
+	createdId, err := client.CreateModule(ctx, id, ModuleModel{
+		GitProviderID: 1,
+		GitTagFormat:  "v{version}",
+		GitPath:       "modules/vpc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if createdId != id {
+		t.Errorf("unexpected ID %v", createdId)
+	}
+
+	modules, err := client.ListNamespaceModules(ctx, "fixture-modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 1 || modules[0] != id {
+		t.Errorf("unexpected modules %v", modules)
+	}
+
+	module, err := client.GetModule(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedModule := ModuleModel{GitProviderID: 1, GitTagFormat: "v{version}", GitPath: "modules/vpc"}
+	if *module != expectedModule {
+		t.Errorf("expected module settings %+v, got %+v", expectedModule, *module)
+	}
+
+	// Updating settings without renaming sends only the settings
+	updatedId, err := client.UpdateModule(ctx, id, ModuleUpdateModel{
+		ModuleModel: &ModuleModel{GitProviderID: 1, GitTagFormat: "{version}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedId != id {
+		t.Errorf("unexpected ID %v", updatedId)
+	}
+
+	// Renaming sends the new namespace, name and provider
+	renamedId, err := client.UpdateModule(ctx, id, ModuleUpdateModel{
+		ModuleModel: &ModuleModel{GitProviderID: 1, GitTagFormat: "{version}"},
+		Namespace:   "fixture-modules",
+		Name:        "network",
+		Provider:    "aws",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedId := ModuleProviderID{Namespace: "fixture-modules", Name: "network", Provider: "aws"}
+	if renamedId != expectedId {
+		t.Errorf("unexpected ID %v", renamedId)
+	}
+
+	module, err = client.GetModule(ctx, renamedId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedModule = ModuleModel{GitProviderID: 1, GitTagFormat: "{version}"}
+	if *module != expectedModule {
+		t.Errorf("expected module settings %+v, got %+v", expectedModule, *module)
+	}
+
+	if err := client.DeleteModule(ctx, renamedId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetModule(ctx, renamedId); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for deleted module, got %v", err)
+	}
+	if err := client.DeleteNamespace(ctx, "fixture-modules"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixtureModuleVersions(t *testing.T) {
+	client := newFixtureClient(t, "module_versions", false)
+	ctx := context.Background()
+
+	if err := client.CreateNamespace(ctx, NamespaceConfigModel{Name: "fixture-versions"}); err != nil {
+		t.Fatal(err)
+	}
+	id := ModuleProviderID{Namespace: "fixture-versions", Name: "vpc", Provider: "aws"}
+	if _, err := client.CreateModule(ctx, id, ModuleModel{GitTagFormat: "v{version}"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.ImportModuleVersion(ctx, id, ModuleVersionImportModel{Version: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadModuleVersion(ctx, id, "1.1.0", strings.NewReader("module archive")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.PublishModuleVersion(ctx, id, "1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteModule(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteNamespace(ctx, "fixture-versions"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixtureModuleVersionsScopedKeys(t *testing.T) {
+	client := newFixtureClient(t, "module_versions_scoped_keys", true)
+	ctx := context.Background()
+
+	// Creating the module uses the admin token
+	if err := client.CreateNamespace(ctx, NamespaceConfigModel{Name: "fixture-scoped-keys"}); err != nil {
+		t.Fatal(err)
+	}
+	id := ModuleProviderID{Namespace: "fixture-scoped-keys", Name: "vpc", Provider: "aws"}
+	if _, err := client.CreateModule(ctx, id, ModuleModel{GitTagFormat: "v{version}"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Importing and uploading use the upload key,
+	// and publishing uses the publish key
+	if err := client.ImportModuleVersion(ctx, id, ModuleVersionImportModel{Version: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadModuleVersion(ctx, id, "1.1.0", strings.NewReader("module archive")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.PublishModuleVersion(ctx, id, "1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteModule(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteNamespace(ctx, "fixture-scoped-keys"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixtureServer(t *testing.T) {
+	client := newFixtureClient(t, "server", false)
+	ctx := context.Background()
+
+	if err := client.DiscoverServices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DetectCapabilities(ctx); err != nil {
+		t.Fatal(err)
+	}
+	capabilities := client.Capabilities()
+	if capabilities.Version == nil {
+		t.Error("expected version to be detected")
+	}
+	// Terrareg is configured as described in the README
+	if config := capabilities.Config; config == nil {
+		t.Error("expected config to be detected")
+	} else if config.AllowCustomGitUrlModuleProvider == nil || !*config.AllowCustomGitUrlModuleProvider {
+		t.Errorf("expected custom git URLs to be allowed, got %v", config.AllowCustomGitUrlModuleProvider)
+	}
+
+	gitProviders, err := client.GetGitProviders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedGitProviders := []GitProviderModel{{ID: 1, Name: "Github"}, {ID: 2, Name: "Bitbucket"}, {ID: 3, Name: "Gitlab"}}
+	if !reflect.DeepEqual(gitProviders, expectedGitProviders) {
+		t.Errorf("expected git providers %v, got %v", expectedGitProviders, gitProviders)
+	}
+
+	status, err := client.GetAuthenticationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Authenticated || !status.ReadAccess || !status.SiteAdmin {
+		t.Errorf("unexpected authentication status %+v", status)
+	}
+}
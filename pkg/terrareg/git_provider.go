@@ -0,0 +1,53 @@
+package terrareg
+
+import (
+	"context"
+)
+
+// GitProviderModel is a git provider configured in Terrareg
+// (GIT_PROVIDER_CONFIG), which modules may use for their repository URLs.
+type GitProviderModel struct {
+	ID   int64  `json:"id" tfsdk:"id"`
+	Name string `json:"name" tfsdk:"name"`
+}
+
+// GetGitProviders returns the git providers configured in Terrareg.
+//
+// The response is cached by the client, as git providers can only
+// be modified by reconfiguring Terrareg.
+func (c *TerraregClient) GetGitProviders(ctx context.Context) ([]GitProviderModel, error) {
+	data, err := c.cache.get(ctx, cacheKeyGitProviders, func(ctx context.Context) (any, error) {
+		return c.getGitProviders(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Return a copy, so that the cached value cannot be modified
+	return append([]GitProviderModel{}, data.([]GitProviderModel)...), nil
+}
+
+func (c *TerraregClient) getGitProviders(ctx context.Context) ([]GitProviderModel, error) {
+	url := c.getTerraregApiUrl("git_providers")
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, newApiError(res, ErrUnknownError)
+	}
+
+	// Body is 200
+	var data []GitProviderModel
+	err = c.decodeResponse(ctx, res, &data, "git providers")
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
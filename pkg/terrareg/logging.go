@@ -0,0 +1,146 @@
+package terrareg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Name of the tflog subsystem used for client logs
+const logSubsystem = "terrareg-client"
+
+// Environment variable that can be used to set the log level of the
+// client subsystem, independently of the provider log level
+const logLevelEnvVar = "TF_LOG_PROVIDER_TERRAREG_CLIENT"
+
+// Value used in place of redacted values in logs
+const redactedValue = "***"
+
+// Headers that contain credentials and must never be logged
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	headerAdminApiKey,
+	headerUploadApiKey,
+	headerPublishApiKey,
+}
+
+// logContext sets up the client logging subsystem, ensuring that
+// any credentials used by the client are masked from all log output.
+func (c *TerraregClient) logContext(ctx context.Context) context.Context {
+	if os.Getenv(logLevelEnvVar) != "" {
+		ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithRootFields(), tflog.WithLevelFromEnv(logLevelEnvVar))
+	} else {
+		ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithRootFields())
+	}
+
+	secrets := c.secrets()
+	if len(secrets) > 0 {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, logSubsystem, secrets...)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, logSubsystem, secrets...)
+	}
+	return ctx
+}
+
+// secrets returns all credentials that are configured for the client
+func (c *TerraregClient) secrets() []string {
+	var secrets []string
+	if c.config.AdminToken != "" {
+		secrets = append(secrets, c.config.AdminToken)
+	}
+	if c.config.BearerToken != "" {
+		secrets = append(secrets, c.config.BearerToken)
+	}
+	if c.config.UploadApiKey != "" {
+		secrets = append(secrets, c.config.UploadApiKey)
+	}
+	if c.config.PublishApiKey != "" {
+		secrets = append(secrets, c.config.PublishApiKey)
+	}
+	return secrets
+}
+
+// redactHeaders converts headers to a map for logging,
+// replacing the values of any sensitive headers.
+func redactHeaders(headers http.Header) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, values := range headers {
+		value := strings.Join(values, ", ")
+		for _, sensitiveHeader := range sensitiveHeaders {
+			if http.CanonicalHeaderKey(sensitiveHeader) == http.CanonicalHeaderKey(name) {
+				value = redactedValue
+				break
+			}
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// logRequest logs the request before it is sent
+func logRequest(ctx context.Context, req *http.Request, body []byte, attempt int) {
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"attempt": attempt + 1,
+	}
+	tflog.SubsystemDebug(ctx, logSubsystem, "Sending request to Terrareg", fields)
+
+	// Only log JSON request bodies, omitting file uploads
+	loggedBody := string(body)
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		loggedBody = fmt.Sprintf("<%d bytes of %s>", len(body), req.Header.Get("Content-Type"))
+	}
+
+	tflog.SubsystemTrace(ctx, logSubsystem, "Request details", map[string]interface{}{
+		"method":          req.Method,
+		"url":             req.URL.String(),
+		"request_headers": redactHeaders(req.Header),
+		"request_body":    loggedBody,
+	})
+}
+
+// logResponse logs the response (or error) from Terrareg.
+//
+// The response body is read in full, so that it can be logged,
+// and replaced with a copy for the caller to read.
+func logResponse(ctx context.Context, req *http.Request, res *http.Response, err error, latencyMs int64) {
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"url":        req.URL.String(),
+		"latency_ms": latencyMs,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemWarn(ctx, logSubsystem, "Request to Terrareg failed", fields)
+		return
+	}
+
+	fields["status"] = res.StatusCode
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received response from Terrareg", fields)
+
+	var body []byte
+	if res.Body != nil {
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			tflog.SubsystemWarn(ctx, logSubsystem, "Unable to read response body", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	tflog.SubsystemTrace(ctx, logSubsystem, "Response details", map[string]interface{}{
+		"method":           req.Method,
+		"url":              req.URL.String(),
+		"status":           res.StatusCode,
+		"response_headers": redactHeaders(res.Header),
+		"response_body":    string(body),
+	})
+}
@@ -0,0 +1,124 @@
+package terrareg
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer bearer-token")
+	headers.Set("Cookie", "session=secret")
+	headers.Set(headerAdminApiKey, "admin-token")
+	headers.Set(headerUploadApiKey, "upload-key")
+	headers.Set(headerPublishApiKey, "publish-key")
+	headers.Set("Content-Type", "application/json")
+	// Headers are matched regardless of case
+	headers["x-terrareg-apikey"] = []string{"lowercase-admin-token"}
+
+	redacted := redactHeaders(headers)
+	for name, value := range redacted {
+		expected := redactedValue
+		if name == "Content-Type" {
+			expected = "application/json"
+		}
+		if value != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, value)
+		}
+	}
+	if len(redacted) != len(headers) {
+		t.Errorf("expected %d headers, got %d", len(headers), len(redacted))
+	}
+}
+
+func TestLogRedaction(t *testing.T) {
+	secrets := map[string]string{
+		"admin":   "admin-token-value",
+		"bearer":  "bearer-token-value",
+		"upload":  "upload-key-value",
+		"publish": "publish-key-value",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Terrareg should never return credentials, but they
+		// must not be logged if it does
+		var credentials []string
+		for _, name := range []string{"Authorization", headerAdminApiKey, headerUploadApiKey, headerPublishApiKey} {
+			if value := r.Header.Get(name); value != "" {
+				credentials = append(credentials, value)
+			}
+		}
+		w.Header().Set("Set-Cookie", "session="+strings.Join(credentials, ","))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"credentials": "` + strings.Join(credentials, ",") + `"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		options []Option
+		scope   authScope
+		header  string
+	}{
+		{"admin token", []Option{WithAdminToken(secrets["admin"])}, authScopeAdmin, headerAdminApiKey},
+		{"bearer token", []Option{WithBearerToken(secrets["bearer"])}, authScopeAdmin, "Authorization"},
+		{"upload key", []Option{WithAdminToken(secrets["admin"]), WithUploadApiKey(secrets["upload"])}, authScopeUpload, headerUploadApiKey},
+		{"publish key", []Option{WithAdminToken(secrets["admin"]), WithPublishApiKey(secrets["publish"])}, authScopePublish, headerPublishApiKey},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(logLevelEnvVar, "TRACE")
+
+			client, err := NewClient(server.URL, test.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var output bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &output)
+			res, err := client.makeRequestWithAuth(ctx, server.URL, http.MethodPost, map[string]string{"name": "example"}, test.scope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			closeBody(res)
+
+			for name, secret := range secrets {
+				if strings.Contains(output.String(), secret) {
+					t.Errorf("%s credential found in log output:\n%s", name, output.String())
+				}
+			}
+
+			entries, err := tflogtest.MultilineJSONDecode(&output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var requestHeaders, responseHeaders map[string]interface{}
+			for _, entry := range entries {
+				switch entry["@message"] {
+				case "Request details":
+					requestHeaders, _ = entry["request_headers"].(map[string]interface{})
+				case "Response details":
+					responseHeaders, _ = entry["response_headers"].(map[string]interface{})
+				}
+			}
+			if requestHeaders == nil || responseHeaders == nil {
+				t.Fatalf("expected request and response details to be logged, got %v", entries)
+			}
+			if value := requestHeaders[http.CanonicalHeaderKey(test.header)]; value != redactedValue {
+				t.Errorf("expected %s request header to be redacted, got %v", test.header, value)
+			}
+			if value := requestHeaders["Content-Type"]; value != "application/json" {
+				t.Errorf("expected Content-Type request header to be logged, got %v", value)
+			}
+			if value := responseHeaders["Set-Cookie"]; value != redactedValue {
+				t.Errorf("expected Set-Cookie response header to be redacted, got %v", value)
+			}
+		})
+	}
+}
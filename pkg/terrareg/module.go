@@ -0,0 +1,174 @@
+package terrareg
+
+import (
+	"context"
+	"fmt"
+)
+
+// ModuleModel contains the settings of a module provider.
+type ModuleModel struct {
+	GitProviderID         int64  `json:"git_provider_id"`
+	RepoBaseUrlTemplate   string `json:"repo_base_url_template"`
+	RepoCloneUrlTemplate  string `json:"repo_clone_url_template"`
+	RepoBrowseUrlTemplate string `json:"repo_browse_url_template"`
+	GitTagFormat          string `json:"git_tag_format"`
+	GitPath               string `json:"git_path"`
+}
+
+// ModuleUpdateModel contains the settings used when updating a module
+// provider. Namespace, Name and Provider are only set when the module
+// is being renamed or moved to a different namespace.
+type ModuleUpdateModel struct {
+	*ModuleModel
+	Namespace string `json:"namespace"`
+	Name      string `json:"module"`
+	Provider  string `json:"provider"`
+}
+
+// CreateModule creates a module provider, returning its ID.
+func (c *TerraregClient) CreateModule(ctx context.Context, id ModuleProviderID, config ModuleModel) (ModuleProviderID, error) {
+	if err := id.Validate(); err != nil {
+		return ModuleProviderID{}, err
+	}
+
+	url := c.getModuleProviderApiUrl(id, "create")
+
+	unlock, err := c.lockNamespaces(ctx, id.Namespace)
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	defer unlock()
+
+	res, err := c.makeRequest(ctx, url, "POST", config)
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	if res.StatusCode != 200 {
+		return ModuleProviderID{}, newApiError(res, ErrUnknownError)
+	}
+
+	type CreateRepsonse struct {
+		ID string `json:"id"`
+	}
+
+	var data CreateRepsonse
+	err = c.decodeResponse(ctx, res, &data, "module")
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	createdId, err := ParseModuleProviderID(data.ID)
+	if err != nil {
+		return ModuleProviderID{}, fmt.Errorf("unable to parse ID of created module: %w", err)
+	}
+	return createdId, nil
+}
+
+// GetModule returns the settings of a module provider.
+func (c *TerraregClient) GetModule(ctx context.Context, id ModuleProviderID) (*ModuleModel, error) {
+	url := c.getModuleProviderApiUrl(id)
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, newApiError(res, ErrUnknownError)
+	}
+
+	// The response contains all details of the module,
+	// of which only the settings are used
+	var data ModuleModel
+	err = c.decodePartialResponse(res, &data, "module")
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// UpdateModule updates the settings of a module provider, returning its
+// ID, which changes if the module provider has been renamed.
+func (c *TerraregClient) UpdateModule(ctx context.Context, id ModuleProviderID, config ModuleUpdateModel) (ModuleProviderID, error) {
+	url := c.getModuleProviderApiUrl(id, "settings")
+
+	unlock, err := c.lockNamespaces(ctx, id.Namespace, config.Namespace)
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	defer unlock()
+
+	// Ignore namespace/name/provider fields if they have not been set
+	var dataToSend interface{}
+	newId := id
+	if config.Namespace != "" && config.Name != "" && config.Provider != "" {
+		newId = ModuleProviderID{
+			Namespace: config.Namespace,
+			Name:      config.Name,
+			Provider:  config.Provider,
+		}
+		if err := newId.Validate(); err != nil {
+			return ModuleProviderID{}, err
+		}
+		dataToSend = config
+	} else {
+		dataToSend = config.ModuleModel
+	}
+
+	res, err := c.makeRequest(ctx, url, "POST", dataToSend)
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return ModuleProviderID{}, err
+	}
+	if res.StatusCode != 200 {
+		return ModuleProviderID{}, newApiError(res, ErrUnknownError)
+	}
+
+	// Terrareg doesn't provide an 'ID' response, at the moment,
+	// so make one up on the fly
+	return newId, nil
+}
+
+// DeleteModule deletes a module provider and all of its versions.
+func (c *TerraregClient) DeleteModule(ctx context.Context, id ModuleProviderID) error {
+	url := c.getModuleProviderApiUrl(id, "delete")
+
+	unlock, err := c.lockNamespaces(ctx, id.Namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Since the namespace DELETE endpoint accepts JSON data,
+	// an empty map must be passed to ensure the request is accepted.
+	res, err := c.makeRequest(ctx, url, "DELETE", map[string]string{})
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package terrareg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Naming rules applied by Terrareg
+var (
+	namespaceNameRegex      = regexp.MustCompile(`^[0-9a-zA-Z](?:[0-9a-zA-Z_-]*[0-9a-zA-Z])?$`)
+	moduleNameRegex         = regexp.MustCompile(`^[0-9a-zA-Z](?:[0-9a-zA-Z_-]*[0-9a-zA-Z])?$`)
+	moduleProviderNameRegex = regexp.MustCompile(`^[0-9a-z]+$`)
+)
+
+// ValidateNamespaceName returns an error if the namespace name is not valid
+// in Terrareg. Names must contain only letters, numbers, dashes and
+// underscores, and must start and end with a letter or number.
+func ValidateNamespaceName(name string) error {
+	if !namespaceNameRegex.MatchString(name) {
+		return fmt.Errorf("namespace %q is invalid: must contain only letters, numbers, dashes and underscores, and must start and end with a letter or number", name)
+	}
+	return nil
+}
+
+// ValidateModuleName returns an error if the module name is not valid
+// in Terrareg. Names must contain only letters, numbers, dashes and
+// underscores, and must start and end with a letter or number.
+func ValidateModuleName(name string) error {
+	if !moduleNameRegex.MatchString(name) {
+		return fmt.Errorf("module name %q is invalid: must contain only letters, numbers, dashes and underscores, and must start and end with a letter or number", name)
+	}
+	return nil
+}
+
+// ValidateModuleProviderName returns an error if the module provider name
+// (e.g. "aws") is not valid in Terrareg. Names must contain only lowercase
+// letters and numbers.
+func ValidateModuleProviderName(name string) error {
+	if !moduleProviderNameRegex.MatchString(name) {
+		return fmt.Errorf("provider %q is invalid: must contain only lowercase letters and numbers", name)
+	}
+	return nil
+}
+
+// ModuleProviderID identifies a module provider in Terrareg,
+// in the form namespace/name/provider.
+type ModuleProviderID struct {
+	Namespace string
+	Name      string
+	Provider  string
+}
+
+// ParseModuleProviderID parses and validates an ID
+// in the form namespace/name/provider.
+func ParseModuleProviderID(id string) (ModuleProviderID, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 {
+		return ModuleProviderID{}, fmt.Errorf("invalid module provider ID %q: must be in the form namespace/name/provider", id)
+	}
+	parsed := ModuleProviderID{
+		Namespace: parts[0],
+		Name:      parts[1],
+		Provider:  parts[2],
+	}
+	if err := parsed.Validate(); err != nil {
+		return ModuleProviderID{}, fmt.Errorf("invalid module provider ID %q: %w", id, err)
+	}
+	return parsed, nil
+}
+
+// Validate returns an error if the namespace, name or provider
+// are not valid in Terrareg.
+func (id ModuleProviderID) Validate() error {
+	if err := ValidateNamespaceName(id.Namespace); err != nil {
+		return err
+	}
+	if err := ValidateModuleName(id.Name); err != nil {
+		return err
+	}
+	return ValidateModuleProviderName(id.Provider)
+}
+
+// String returns the ID in the form namespace/name/provider.
+func (id ModuleProviderID) String() string {
+	return fmt.Sprintf("%s/%s/%s", id.Namespace, id.Name, id.Provider)
+}
+
+// EscapedPath returns the ID as a URL path, escaping each of the segments.
+func (id ModuleProviderID) EscapedPath() string {
+	return escapePathSegments(id.Namespace, id.Name, id.Provider)
+}
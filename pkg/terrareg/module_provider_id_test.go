@@ -0,0 +1,47 @@
+package terrareg
+
+import (
+	"testing"
+)
+
+func TestParseModuleProviderID(t *testing.T) {
+	id, err := ParseModuleProviderID("my-namespace/vpc_module/aws")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := ModuleProviderID{Namespace: "my-namespace", Name: "vpc_module", Provider: "aws"}
+	if id != expected {
+		t.Errorf("expected %#v, got %#v", expected, id)
+	}
+	if id.String() != "my-namespace/vpc_module/aws" {
+		t.Errorf("unexpected string: %q", id.String())
+	}
+
+	for _, invalidId := range []string{
+		"",
+		"namespace/name",
+		"namespace/name/aws/extra",
+		"/name/aws",
+		"namespace//aws",
+		"namespace/name/",
+		"-namespace/name/aws",
+		"namespace/name-/aws",
+		"namespace/na me/aws",
+		"namespace/name/AWS",
+		"namespace/name/a-b",
+	} {
+		if _, err := ParseModuleProviderID(invalidId); err == nil {
+			t.Errorf("%q: expected error", invalidId)
+		}
+	}
+}
+
+func TestModuleProviderIDEscapedPath(t *testing.T) {
+	id := ModuleProviderID{Namespace: "name space", Name: "a/b", Provider: "aws"}
+	if path := id.EscapedPath(); path != "name%20space/a%2Fb/aws" {
+		t.Errorf("unexpected escaped path: %q", path)
+	}
+	if err := id.Validate(); err == nil {
+		t.Errorf("expected validation error")
+	}
+}
@@ -0,0 +1,119 @@
+package terrareg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+)
+
+// ModuleVersionImportModel identifies a module version to import
+// from the git repository of a module provider.
+type ModuleVersionImportModel struct {
+	// Version to import. Either Version or GitTag must be provided.
+	Version string `json:"version,omitempty"`
+	// Git tag to import, for modules with a git tag format
+	// that does not contain the version.
+	GitTag string `json:"git_tag,omitempty"`
+}
+
+// ImportModuleVersion indexes a module version from the module's git repository.
+//
+// Authenticates using the upload API key, if provided.
+func (c *TerraregClient) ImportModuleVersion(ctx context.Context, id ModuleProviderID, config ModuleVersionImportModel) error {
+	url := c.getModuleProviderApiUrl(id, "import")
+
+	unlock, err := c.lockNamespaces(ctx, id.Namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	res, err := c.makeRequestWithAuth(ctx, url, "POST", config, authScopeUpload)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	return nil
+}
+
+// UploadModuleVersion uploads a zip archive of a module version.
+//
+// Authenticates using the upload API key, if provided.
+func (c *TerraregClient) UploadModuleVersion(ctx context.Context, id ModuleProviderID, version string, archive io.Reader) error {
+	url := c.getModuleProviderApiUrl(id, version, "upload")
+
+	unlock, err := c.lockNamespaces(ctx, id.Namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "module.zip")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, archive); err != nil {
+		return fmt.Errorf("unable to read module archive: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	res, err := c.doRequest(ctx, url, "POST", body.Bytes(), writer.FormDataContentType(), authScopeUpload)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	return nil
+}
+
+// PublishModuleVersion publishes a module version that has been indexed.
+//
+// Authenticates using the publish API key, if provided.
+func (c *TerraregClient) PublishModuleVersion(ctx context.Context, id ModuleProviderID, version string) error {
+	url := c.getModuleProviderApiUrl(id, version, "publish")
+
+	unlock, err := c.lockNamespaces(ctx, id.Namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	res, err := c.makeRequestWithAuth(ctx, url, "POST", map[string]string{}, authScopePublish)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	return nil
+}
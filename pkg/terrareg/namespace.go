@@ -0,0 +1,250 @@
+package terrareg
+
+import (
+	"context"
+	"fmt"
+)
+
+// NamespaceModel contains the details of a namespace.
+type NamespaceModel struct {
+	DisplayName    string `json:"display_name"`
+	IsAutoVerified bool   `json:"is_auto_verified"`
+	Trusted        bool   `json:"trusted"`
+}
+
+// NamespaceSummaryModel is a namespace returned when listing namespaces
+type NamespaceSummaryModel struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// NamespaceConfigModel contains the settings used when creating or
+// updating a namespace.
+type NamespaceConfigModel struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// CreateNamespace creates a namespace.
+func (c *TerraregClient) CreateNamespace(ctx context.Context, config NamespaceConfigModel) error {
+
+	url := c.getTerraregApiUrl("namespaces")
+
+	unlock, err := c.lockNamespaces(ctx, config.Name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer c.cache.invalidate(cacheKeyNamespaces)
+
+	res, err := c.makeRequest(ctx, url, "POST", config)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+	return nil
+}
+
+// ListNamespaces returns all namespaces in Terrareg.
+//
+// The response is cached by the client and invalidated when
+// namespaces are modified by the client.
+func (c *TerraregClient) ListNamespaces(ctx context.Context) ([]NamespaceSummaryModel, error) {
+	data, err := c.cache.get(ctx, cacheKeyNamespaces, func(ctx context.Context) (any, error) {
+		return c.listNamespaces(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Return a copy, so that the cached value cannot be modified
+	return append([]NamespaceSummaryModel{}, data.([]NamespaceSummaryModel)...), nil
+}
+
+func (c *TerraregClient) listNamespaces(ctx context.Context) ([]NamespaceSummaryModel, error) {
+	url := c.getTerraregApiUrl("namespaces")
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, newApiError(res, ErrUnknownError)
+	}
+
+	var data []NamespaceSummaryModel
+	err = c.decodeResponse(ctx, res, &data, "namespaces")
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Number of module providers requested per page when listing modules
+const namespaceModulesPageSize = 50
+
+// ListNamespaceModules returns the IDs of all module providers in a
+// namespace, including module providers without any published versions.
+func (c *TerraregClient) ListNamespaceModules(ctx context.Context, name string) ([]ModuleProviderID, error) {
+	var ids []ModuleProviderID
+	offset := 0
+	for {
+		page, nextOffset, err := c.listNamespaceModulesPage(ctx, name, offset)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, page...)
+
+		// Guard against a server that does not advance the offset
+		if nextOffset == nil || *nextOffset <= offset || len(page) == 0 {
+			return ids, nil
+		}
+		offset = *nextOffset
+	}
+}
+
+// listNamespaceModulesPage returns a page of module providers in a
+// namespace, along with the offset of the next page, if any.
+func (c *TerraregClient) listNamespaceModulesPage(ctx context.Context, name string, offset int) ([]ModuleProviderID, *int, error) {
+	url := fmt.Sprintf("%s?offset=%d&limit=%d", c.getTerraregApiUrl("modules", name), offset, namespaceModulesPageSize)
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, nil, newApiError(res, ErrUnknownError)
+	}
+
+	// The response contains all details of each module,
+	// of which only the ID is used
+	var data struct {
+		Meta struct {
+			NextOffset *int `json:"next_offset"`
+		} `json:"meta"`
+		Modules []struct {
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+			Provider  string `json:"provider"`
+		} `json:"modules"`
+	}
+	err = c.decodePartialResponse(res, &data, "namespace modules")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]ModuleProviderID, 0, len(data.Modules))
+	for _, module := range data.Modules {
+		ids = append(ids, ModuleProviderID{
+			Namespace: module.Namespace,
+			Name:      module.Name,
+			Provider:  module.Provider,
+		})
+	}
+	return ids, data.Meta.NextOffset, nil
+}
+
+// GetNamespace returns the details of a namespace.
+func (c *TerraregClient) GetNamespace(ctx context.Context, name string) (*NamespaceModel, error) {
+	url := c.getTerraregApiUrl("namespaces", name)
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, newApiError(res, ErrUnknownError)
+	}
+
+	// Body is 200
+	var namespace NamespaceModel
+	err = c.decodeResponse(ctx, res, &namespace, "namespace")
+	if err != nil {
+		return nil, err
+	}
+	return &namespace, nil
+}
+
+// UpdateNamespace updates the settings of a namespace. Setting a
+// different name in the config renames the namespace.
+func (c *TerraregClient) UpdateNamespace(ctx context.Context, name string, config NamespaceConfigModel) error {
+	url := c.getTerraregApiUrl("namespaces", name)
+
+	unlock, err := c.lockNamespaces(ctx, name, config.Name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer c.cache.invalidate(cacheKeyNamespaces)
+
+	res, err := c.makeRequest(ctx, url, "POST", config)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	return nil
+}
+
+// DeleteNamespace deletes a namespace, which must not contain any modules.
+func (c *TerraregClient) DeleteNamespace(ctx context.Context, name string) error {
+	url := c.getTerraregApiUrl("namespaces", name)
+
+	unlock, err := c.lockNamespaces(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer c.cache.invalidate(cacheKeyNamespaces)
+
+	// Since the namespace DELETE endpoint accepts JSON data,
+	// an empty map must be passed to ensure the request is accepted.
+	res, err := c.makeRequest(ctx, url, "DELETE", map[string]string{})
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != 200 {
+		return newApiError(res, ErrUnknownError)
+	}
+
+	return nil
+}
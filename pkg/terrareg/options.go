@@ -0,0 +1,179 @@
+package terrareg
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a TerraregClient created using NewClient.
+type Option func(*clientConfig)
+
+// WithAdminToken authenticates requests using the Terrareg admin
+// authentication token (ADMIN_AUTHENTICATION_TOKEN).
+func WithAdminToken(token string) Option {
+	return func(c *clientConfig) {
+		c.AdminToken = token
+	}
+}
+
+// WithBearerToken authenticates requests using a bearer Authorization
+// header, such as a token obtained using TerraformCLIToken. The token is
+// only used if an admin token has not been provided.
+func WithBearerToken(token string) Option {
+	return func(c *clientConfig) {
+		c.BearerToken = token
+	}
+}
+
+// WithUploadApiKey sets the API key used for importing and uploading
+// module versions. If not provided, the admin token is used.
+func WithUploadApiKey(key string) Option {
+	return func(c *clientConfig) {
+		c.UploadApiKey = key
+	}
+}
+
+// WithPublishApiKey sets the API key used for publishing module
+// versions. If not provided, the admin token is used.
+func WithPublishApiKey(key string) Option {
+	return func(c *clientConfig) {
+		c.PublishApiKey = key
+	}
+}
+
+// WithMaxRetries sets the maximum number of times a failed request
+// is retried. 0 disables retries. Defaults to DefaultMaxRetries.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *clientConfig) {
+		c.MaxRetries = maxRetries
+	}
+}
+
+// WithRetryWait sets the minimum and maximum time to wait between
+// retries. Defaults to DefaultRetryWaitMin and DefaultRetryWaitMax.
+func WithRetryWait(min time.Duration, max time.Duration) Option {
+	return func(c *clientConfig) {
+		c.RetryWaitMin = min
+		c.RetryWaitMax = max
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections kept
+// open to Terrareg. Defaults to DefaultMaxIdleConns.
+func WithMaxIdleConns(maxIdleConns int) Option {
+	return func(c *clientConfig) {
+		c.MaxIdleConns = maxIdleConns
+	}
+}
+
+// WithRequestTimeout sets the timeout of each request attempt.
+// 0 disables the timeout, except for cached requests, which are limited
+// to DefaultRequestTimeout. Defaults to DefaultRequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *clientConfig) {
+		c.RequestTimeout = timeout
+	}
+}
+
+// WithCompressionDisabled disables requesting gzip compressed responses.
+func WithCompressionDisabled() Option {
+	return func(c *clientConfig) {
+		c.DisableCompression = true
+	}
+}
+
+// WithCACertificate trusts the PEM-encoded CA certificate(s), in addition
+// to the system CAs, when connecting to Terrareg.
+func WithCACertificate(certPEM string) Option {
+	return func(c *clientConfig) {
+		c.CACertPEM = certPEM
+	}
+}
+
+// WithClientCertificate authenticates to Terrareg using mutual TLS,
+// with the PEM-encoded client certificate and private key.
+func WithClientCertificate(certPEM string, keyPEM string) Option {
+	return func(c *clientConfig) {
+		c.ClientCertPEM = certPEM
+		c.ClientKeyPEM = keyPEM
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the Terrareg
+// server certificate. This should only be used for testing.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(c *clientConfig) {
+		c.InsecureSkipVerify = skip
+	}
+}
+
+// WithProxyURL sets the proxy used for all requests. If not set, the proxy
+// is obtained from the HTTP_PROXY/HTTPS_PROXY environment variables.
+func WithProxyURL(proxyUrl string) Option {
+	return func(c *clientConfig) {
+		c.ProxyURL = proxyUrl
+	}
+}
+
+// WithUserAgent sets the User-Agent sent with all requests.
+// Defaults to DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *clientConfig) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithHeaders sets additional static headers sent with all requests.
+// These cannot override the headers set by the client, such as
+// authentication headers.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *clientConfig) {
+		c.Headers = headers
+	}
+}
+
+// WithRateLimit limits requests to rate requests per second, allowing
+// bursts of up to burst requests. A rate of 0 disables rate limiting,
+// which is the default.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *clientConfig) {
+		c.RateLimit = rate
+		c.RateLimitBurst = burst
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of requests in-flight
+// at once. 0 disables the limit, which is the default.
+func WithMaxConcurrentRequests(maxConcurrentRequests int) Option {
+	return func(c *clientConfig) {
+		c.MaxConcurrentRequests = maxConcurrentRequests
+	}
+}
+
+// WithCacheTTL sets the time that read-mostly listings, such as git
+// providers, are cached. 0 disables caching. Defaults to DefaultCacheTTL.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *clientConfig) {
+		c.CacheTTL = ttl
+	}
+}
+
+// WithStrictDecoding returns an error if a response contains fields that
+// are not known to the client, rather than ignoring them. Unknown fields
+// are otherwise collected using CollectUnknownFields and reported by
+// TakeUnknownFields.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *clientConfig) {
+		c.StrictDecoding = strict
+	}
+}
+
+// WithTransport sends requests using the transport, rather than the
+// default transport, e.g. to record or replay requests in tests.
+// Options configuring TLS, proxies, compression and idle connections
+// are not applied to the transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *clientConfig) {
+		c.Transport = transport
+	}
+}
@@ -0,0 +1,149 @@
+package terrareg
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket rate limiter, allowing bursts of
+// up to burst requests and refilling at rate requests per second.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request is permitted by the rate limiter,
+// or the context is cancelled.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	// Reserve a token, calculating how long to wait for it to be available
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		// Return the unused token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// semaphore limits the number of requests in-flight at once
+type semaphore chan struct{}
+
+func newSemaphore(size int) semaphore {
+	if size <= 0 {
+		return nil
+	}
+	return make(semaphore, size)
+}
+
+// Acquire blocks until a slot is available or the context is cancelled.
+func (s semaphore) Acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) Release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
+
+// namespaceLocks serializes mutating requests within each namespace,
+// avoiding database lock errors in Terrareg when Terraform modifies
+// several resources in a namespace in parallel.
+type namespaceLocks struct {
+	mu    sync.Mutex
+	locks map[string]semaphore
+}
+
+func (n *namespaceLocks) get(namespace string) semaphore {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.locks == nil {
+		n.locks = make(map[string]semaphore)
+	}
+	lock, ok := n.locks[namespace]
+	if !ok {
+		lock = newSemaphore(1)
+		n.locks[namespace] = lock
+	}
+	return lock
+}
+
+// lockNamespaces obtains the locks for each of the namespaces,
+// returning a function to release them. Locks are always obtained
+// in the same order, so that requests affecting multiple namespaces
+// (e.g. moving a module between namespaces) cannot deadlock.
+func (c *TerraregClient) lockNamespaces(ctx context.Context, namespaces ...string) (func(), error) {
+	sorted := make([]string, 0, len(namespaces))
+	seen := make(map[string]bool)
+	for _, namespace := range namespaces {
+		if namespace != "" && !seen[namespace] {
+			seen[namespace] = true
+			sorted = append(sorted, namespace)
+		}
+	}
+	sort.Strings(sorted)
+
+	var acquired []semaphore
+	unlock := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			acquired[i].Release()
+		}
+	}
+	for _, namespace := range sorted {
+		lock := c.namespaceLocks.get(namespace)
+		if err := lock.Acquire(ctx); err != nil {
+			unlock()
+			return nil, err
+		}
+		acquired = append(acquired, lock)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,91 @@
+package terrareg
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The burst of 2 is permitted immediately, the remaining
+	// 2 requests wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestRateLimiter_cancelled(t *testing.T) {
+	limiter := newRateLimiter(0.001, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("expected error when context is cancelled")
+	}
+}
+
+func TestRateLimiter_disabled(t *testing.T) {
+	if limiter := newRateLimiter(0, 0); limiter != nil {
+		t.Error("expected rate limiter to be disabled")
+	}
+}
+
+func TestLockNamespaces(t *testing.T) {
+	client, err := NewClient("https://terrareg.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Locking namespaces in opposing orders must not deadlock
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		namespaces := []string{"first", "second"}
+		if i%2 == 0 {
+			namespaces = []string{"second", "first", "second"}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := client.lockNamespaces(ctx, namespaces...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			unlock()
+		}()
+	}
+	wg.Wait()
+
+	// A held lock blocks further requests in the namespace
+	unlock, err := client.lockNamespaces(ctx, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.lockNamespaces(timeoutCtx, "first"); err == nil {
+		t.Error("expected namespace lock to be held")
+	}
+	otherUnlock, err := client.lockNamespaces(ctx, "second")
+	if err != nil {
+		t.Errorf("expected lock for other namespace to be obtained: %s", err)
+	} else {
+		otherUnlock()
+	}
+}
@@ -0,0 +1,129 @@
+package terrareg
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 4
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// isIdempotentMethod returns whether a request using the HTTP method
+// can safely be sent more than once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isConnectionRefused returns whether the error occurred whilst establishing
+// the connection, meaning that the request was never sent to Terrareg.
+func isConnectionRefused(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// shouldRetry determines whether a request should be retried, based on
+// the response or error from the previous attempt.
+//
+// Non-idempotent requests (e.g. POST) are only retried if it is known that
+// Terrareg did not process the request: when the connection could not be
+// established, or when the server responded with 429 or 503.
+func (c *TerraregClient) shouldRetry(ctx context.Context, method string, res *http.Response, err error) bool {
+	// Never retry if the caller has cancelled the request
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotentMethod(method) || isConnectionRefused(err)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotentMethod(method)
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header of a response, which may
+// either be a number of seconds or a HTTP date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+	header := res.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// backoff calculates the time to wait before the next attempt,
+// using exponential backoff with jitter, honouring any Retry-After
+// header returned by Terrareg.
+func (c *TerraregClient) backoff(attempt int, res *http.Response) time.Duration {
+	waitMin := c.config.RetryWaitMin
+	waitMax := c.config.RetryWaitMax
+
+	if wait, ok := retryAfter(res); ok {
+		if wait > waitMax {
+			wait = waitMax
+		}
+		return wait
+	}
+
+	wait := float64(waitMin) * math.Pow(2, float64(attempt))
+	if wait > float64(waitMax) {
+		wait = float64(waitMax)
+	}
+
+	// Randomise between half and the full wait time, to avoid
+	// parallel requests retrying in lock-step.
+	jittered := time.Duration(wait/2 + rand.Float64()*wait/2)
+	if jittered < waitMin {
+		jittered = waitMin
+	}
+	return jittered
+}
+
+// sleep waits for the given duration, returning early with an error
+// if the context is cancelled.
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,291 @@
+package terrareg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	client, err := NewClient("https://terrareg.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	dnsErr := &net.DNSError{Err: "no such host", Name: "terrareg.example.com"}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		err        error
+		expected   bool
+	}{
+		{"GET 200", http.MethodGet, http.StatusOK, nil, false},
+		{"GET 400", http.MethodGet, http.StatusBadRequest, nil, false},
+		{"GET 404", http.MethodGet, http.StatusNotFound, nil, false},
+		{"GET 429", http.MethodGet, http.StatusTooManyRequests, nil, true},
+		{"GET 500", http.MethodGet, http.StatusInternalServerError, nil, false},
+		{"GET 502", http.MethodGet, http.StatusBadGateway, nil, true},
+		{"GET 503", http.MethodGet, http.StatusServiceUnavailable, nil, true},
+		{"GET 504", http.MethodGet, http.StatusGatewayTimeout, nil, true},
+		{"PUT 502", http.MethodPut, http.StatusBadGateway, nil, true},
+		{"DELETE 504", http.MethodDelete, http.StatusGatewayTimeout, nil, true},
+		{"POST 429", http.MethodPost, http.StatusTooManyRequests, nil, true},
+		{"POST 503", http.MethodPost, http.StatusServiceUnavailable, nil, true},
+		{"POST 502", http.MethodPost, http.StatusBadGateway, nil, false},
+		{"POST 504", http.MethodPost, http.StatusGatewayTimeout, nil, false},
+		{"PATCH 502", http.MethodPatch, http.StatusBadGateway, nil, false},
+		{"GET dial error", http.MethodGet, 0, dialErr, true},
+		{"GET read error", http.MethodGet, 0, readErr, true},
+		{"GET unexpected EOF", http.MethodGet, 0, io.ErrUnexpectedEOF, true},
+		{"POST dial error", http.MethodPost, 0, dialErr, true},
+		{"POST DNS error", http.MethodPost, 0, dnsErr, true},
+		{"POST read error", http.MethodPost, 0, readErr, false},
+		{"POST unexpected EOF", http.MethodPost, 0, io.ErrUnexpectedEOF, false},
+	}
+	for _, test := range tests {
+		var res *http.Response
+		if test.err == nil {
+			res = &http.Response{StatusCode: test.statusCode}
+		}
+		if retry := client.shouldRetry(context.Background(), test.method, res, test.err); retry != test.expected {
+			t.Errorf("%s: expected retry to be %t, got %t", test.name, test.expected, retry)
+		}
+	}
+
+	// Requests are never retried once the caller has cancelled them
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if client.shouldRetry(ctx, http.MethodGet, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil) {
+		t.Error("expected cancelled request not to be retried")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	waitMin := 100 * time.Millisecond
+	waitMax := time.Second
+	client, err := NewClient("https://terrareg.example.com", WithRetryWait(waitMin, waitMax))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		// The wait is at least the minimum wait, even after jitter
+		{0, waitMin, waitMin},
+		{1, waitMin, 200 * time.Millisecond},
+		{2, 200 * time.Millisecond, 400 * time.Millisecond},
+		{3, 400 * time.Millisecond, 800 * time.Millisecond},
+		// The wait is capped at the maximum wait
+		{4, 500 * time.Millisecond, waitMax},
+		{10, 500 * time.Millisecond, waitMax},
+	}
+	for _, test := range tests {
+		// Repeat, since the wait is randomised
+		for i := 0; i < 100; i++ {
+			if wait := client.backoff(test.attempt, nil); wait < test.min || wait > test.max {
+				t.Errorf("attempt %d: expected wait between %s and %s, got %s", test.attempt, test.min, test.max, wait)
+				break
+			}
+		}
+	}
+}
+
+func TestBackoff_retryAfter(t *testing.T) {
+	waitMin := 100 * time.Millisecond
+	waitMax := 10 * time.Second
+	client, err := NewClient("https://terrareg.example.com", WithRetryWait(waitMin, waitMax))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		retryAfter string
+		min        time.Duration
+		max        time.Duration
+	}{
+		{"0", 0, 0},
+		{"3", 3 * time.Second, 3 * time.Second},
+		// Capped at the maximum wait
+		{"3600", waitMax, waitMax},
+		{time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat), 3 * time.Second, 5 * time.Second},
+		// Dates in the past do not wait
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+		// Invalid values fall back to exponential backoff
+		{"-1", waitMin, waitMin},
+		{"soon", waitMin, waitMin},
+	}
+	for _, test := range tests {
+		res := &http.Response{Header: http.Header{"Retry-After": []string{test.retryAfter}}}
+		if wait := client.backoff(0, res); wait < test.min || wait > test.max {
+			t.Errorf("Retry-After %q: expected wait between %s and %s, got %s", test.retryAfter, test.min, test.max, wait)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		statusCodes      []int
+		maxRetries       int
+		expectedAttempts int32
+		expectedStatus   int
+	}{
+		{"success", http.MethodGet, []int{http.StatusOK}, 4, 1, http.StatusOK},
+		{"retried until success", http.MethodGet, []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, 4, 3, http.StatusOK},
+		{"max retries", http.MethodGet, []int{http.StatusServiceUnavailable}, 2, 3, http.StatusServiceUnavailable},
+		{"retries disabled", http.MethodGet, []int{http.StatusServiceUnavailable}, 0, 1, http.StatusServiceUnavailable},
+		{"not retryable", http.MethodGet, []int{http.StatusInternalServerError, http.StatusOK}, 4, 1, http.StatusInternalServerError},
+		{"POST rate limited", http.MethodPost, []int{http.StatusTooManyRequests, http.StatusOK}, 4, 2, http.StatusOK},
+		{"POST bad gateway", http.MethodPost, []int{http.StatusBadGateway, http.StatusOK}, 4, 1, http.StatusBadGateway},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(atomic.AddInt32(&attempts, 1)) - 1
+				if attempt >= len(test.statusCodes) {
+					attempt = len(test.statusCodes) - 1
+				}
+				w.WriteHeader(test.statusCodes[attempt])
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL, WithMaxRetries(test.maxRetries), WithRetryWait(time.Millisecond, time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := client.makeRequest(context.Background(), server.URL, test.method, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			closeBody(res)
+			if res.StatusCode != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, res.StatusCode)
+			}
+			if attempts := atomic.LoadInt32(&attempts); attempts != test.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", test.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetry_retryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Retry-After takes precedence over the minimum wait
+	client, err := NewClient(server.URL, WithRetryWait(time.Minute, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	res, err := client.makeRequest(context.Background(), server.URL, http.MethodGet, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeBody(res)
+	if attempts := atomic.LoadInt32(&attempts); res.StatusCode != http.StatusOK || attempts != 2 {
+		t.Errorf("expected success after 2 attempts, got status %d after %d attempts", res.StatusCode, attempts)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected Retry-After to be honoured, took %s", elapsed)
+	}
+}
+
+// errorTransport fails every request with the error, counting attempts.
+type errorTransport struct {
+	err      error
+	attempts int32
+}
+
+func (t *errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.attempts, 1)
+	return nil, t.err
+}
+
+func TestRetry_networkErrors(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name             string
+		method           string
+		err              error
+		expectedAttempts int32
+	}{
+		{"GET dial error", http.MethodGet, dialErr, 3},
+		{"GET unexpected EOF", http.MethodGet, io.ErrUnexpectedEOF, 3},
+		{"POST dial error", http.MethodPost, dialErr, 3},
+		// The request may have been processed by Terrareg
+		{"POST unexpected EOF", http.MethodPost, io.ErrUnexpectedEOF, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := &errorTransport{err: test.err}
+			client, err := NewClient(
+				"https://terrareg.example.com",
+				WithTransport(transport),
+				WithMaxRetries(2),
+				WithRetryWait(time.Millisecond, time.Millisecond),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.makeRequest(context.Background(), "https://terrareg.example.com/v1/terrareg/git_providers", test.method, nil)
+			if !errors.Is(err, test.err) {
+				t.Errorf("expected error %v, got %v", test.err, err)
+			}
+			if attempts := atomic.LoadInt32(&transport.attempts); attempts != test.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", test.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetry_cancelled(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetryWait(time.Minute, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The context is cancelled whilst waiting to retry
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.makeRequest(ctx, server.URL, http.MethodGet, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if attempts := atomic.LoadInt32(&attempts); attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
@@ -0,0 +1,129 @@
+package terrareg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ServerConfigModel contains the subset of the Terrareg server
+// configuration that affects the behaviour of the client.
+//
+// Settings are nil if not returned by Terrareg (e.g. by versions that
+// predate the setting), in which case their value is unknown.
+type ServerConfigModel struct {
+	AllowCustomGitUrlModuleProvider *bool `json:"ALLOW_CUSTOM_GIT_URL_MODULE_PROVIDER"`
+	UploadApiKeysEnabled            *bool `json:"UPLOAD_API_KEYS_ENABLED"`
+	PublishApiKeysEnabled           *bool `json:"PUBLISH_API_KEYS_ENABLED"`
+}
+
+// Capabilities describes the Terrareg server that the client is connected to.
+type Capabilities struct {
+	// Version of Terrareg, nil if the version could not be determined
+	Version *version.Version
+	// Server configuration, nil if the configuration could not be obtained
+	Config *ServerConfigModel
+}
+
+// GetServerVersion returns the version of Terrareg.
+func (c *TerraregClient) GetServerVersion(ctx context.Context) (string, error) {
+	url := c.getTerraregApiUrl("version")
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != 200 {
+		return "", newApiError(res, ErrUnknownError)
+	}
+
+	var data struct {
+		Version string `json:"version"`
+	}
+	err = c.decodeResponse(ctx, res, &data, "version")
+	if err != nil {
+		return "", err
+	}
+	return data.Version, nil
+}
+
+// GetServerConfig returns the configuration of Terrareg.
+//
+// The response is cached by the client.
+func (c *TerraregClient) GetServerConfig(ctx context.Context) (*ServerConfigModel, error) {
+	data, err := c.cache.get(ctx, cacheKeyServerConfig, func(ctx context.Context) (any, error) {
+		return c.getServerConfig(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	config := *data.(*ServerConfigModel)
+	return &config, nil
+}
+
+func (c *TerraregClient) getServerConfig(ctx context.Context) (*ServerConfigModel, error) {
+	url := c.getTerraregApiUrl("config")
+
+	res, err := c.makeRequest(ctx, url, "GET", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(res)
+
+	err = c.handleCommonStatusCode(res)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		return nil, newApiError(res, ErrUnknownError)
+	}
+
+	// The response contains all configuration of Terrareg,
+	// of which only the settings used by the provider are decoded
+	var data ServerConfigModel
+	err = c.decodePartialResponse(res, &data, "config")
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// DetectCapabilities queries the version and configuration of Terrareg,
+// caching the result on the client.
+//
+// Any information that cannot be obtained (e.g. if the server is too old to
+// provide the version endpoint) is left unset and the errors are returned, so
+// that the caller may decide whether to continue.
+func (c *TerraregClient) DetectCapabilities(ctx context.Context) error {
+	capabilities := &Capabilities{}
+	var errs []error
+
+	serverVersion, err := c.GetServerVersion(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to obtain Terrareg version: %w", err))
+	} else if capabilities.Version, err = version.NewVersion(serverVersion); err != nil {
+		errs = append(errs, fmt.Errorf("unable to parse Terrareg version %q: %w", serverVersion, err))
+	}
+
+	capabilities.Config, err = c.GetServerConfig(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to obtain Terrareg config: %w", err))
+	}
+
+	c.capabilities = capabilities
+	return errors.Join(errs...)
+}
+
+// Capabilities returns the detected capabilities of the server,
+// or nil if DetectCapabilities has not been called.
+func (c *TerraregClient) Capabilities() *Capabilities {
+	return c.capabilities
+}
@@ -0,0 +1,50 @@
+package terrareg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServerTestClient(t *testing.T, config string) *TerraregClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/terrareg/version":
+			_, _ = w.Write([]byte(`{"version": "2.50.0"}`))
+		case "/v1/terrareg/config":
+			_, _ = w.Write([]byte(config))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, WithMaxRetries(0), WithCacheTTL(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestGetServerConfig(t *testing.T) {
+	client := newServerTestClient(t, `{"ALLOW_CUSTOM_GIT_URL_MODULE_PROVIDER": false, "UPLOAD_API_KEYS_ENABLED": true}`)
+
+	config, err := client.GetServerConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.AllowCustomGitUrlModuleProvider == nil || *config.AllowCustomGitUrlModuleProvider {
+		t.Errorf("expected custom git URLs to be disallowed, got %v", config.AllowCustomGitUrlModuleProvider)
+	}
+	if config.UploadApiKeysEnabled == nil || !*config.UploadApiKeysEnabled {
+		t.Errorf("expected upload API keys to be enabled, got %v", config.UploadApiKeysEnabled)
+	}
+	// Settings not returned by Terrareg are unknown, rather than false
+	if config.PublishApiKeysEnabled != nil {
+		t.Errorf("expected publish API keys setting to be unknown, got %v", *config.PublishApiKeysEnabled)
+	}
+}
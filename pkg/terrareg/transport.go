@@ -0,0 +1,105 @@
+package terrareg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultMaxIdleConns   = 10
+	DefaultRequestTimeout = 2 * time.Minute
+)
+
+// Maximum amount of an unread response body that is read before
+// closing, to allow the connection to be re-used
+const maxDrainBodyLength = 64 * 1024
+
+// newHttpClient creates the long-lived HTTP client used for all requests.
+//
+// Since the client only talks to a single Terrareg host, idle connections
+// are limited per-host to the same value as the total idle connections.
+func newHttpClient(config clientConfig) (*http.Client, error) {
+	if config.Transport != nil {
+		return &http.Client{
+			Transport: config.Transport,
+			Timeout:   config.RequestTimeout,
+		}, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = config.MaxIdleConns
+	transport.DisableCompression = config.DisableCompression
+
+	tlsConfig, err := newTlsConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if config.ProxyURL != "" {
+		proxyUrl, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if proxyUrl.Scheme == "" || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: must include scheme and host", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   config.RequestTimeout,
+	}, nil
+}
+
+// newTlsConfig creates the TLS configuration for connecting to Terrareg,
+// adding any custom CA and client certificate.
+func newTlsConfig(config clientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Allow disabling verification, for Terrareg instances
+		// using self-signed certificates
+		InsecureSkipVerify: config.InsecureSkipVerify, //nolint:gosec
+	}
+
+	if config.CACertPEM != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(config.CACertPEM)) {
+			return nil, fmt.Errorf("unable to parse CA certificate: no valid PEM certificates found")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if config.ClientCertPEM != "" || config.ClientKeyPEM != "" {
+		if config.ClientCertPEM == "" || config.ClientKeyPEM == "" {
+			return nil, fmt.Errorf("both a client certificate and client key must be provided for mutual TLS")
+		}
+		clientCert, err := tls.X509KeyPair([]byte(config.ClientCertPEM), []byte(config.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// closeBody drains and closes the body of a response,
+// so that the underlying connection can be re-used.
+func closeBody(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, res.Body, maxDrainBodyLength)
+	res.Body.Close()
+}
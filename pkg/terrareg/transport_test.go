@@ -0,0 +1,326 @@
+package terrareg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// trackedBody is a response body that records whether it has been
+// read to the end and closed.
+type trackedBody struct {
+	io.Reader
+	mu     sync.Mutex
+	eof    bool
+	closed int
+}
+
+func newTrackedBody(body string) *trackedBody {
+	return &trackedBody{Reader: strings.NewReader(body)}
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.mu.Lock()
+		b.eof = true
+		b.mu.Unlock()
+	}
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed++
+	return nil
+}
+
+func (b *trackedBody) state() (bool, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.eof, b.closed
+}
+
+// trackingTransport responds with the status codes in turn, recording
+// the body of each response, and checks that the body of the previous
+// response was drained and closed before the next request is sent.
+type trackingTransport struct {
+	t           *testing.T
+	statusCodes []int
+	bodies      []*trackedBody
+}
+
+func (tr *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(tr.bodies) > 0 {
+		eof, closed := tr.bodies[len(tr.bodies)-1].state()
+		if !eof || closed != 1 {
+			tr.t.Errorf("attempt %d sent before previous response body was drained and closed (drained: %t, closed: %d times)", len(tr.bodies)+1, eof, closed)
+		}
+	}
+
+	body := newTrackedBody(strings.Repeat("x", 1024))
+	tr.bodies = append(tr.bodies, body)
+	return &http.Response{
+		StatusCode: tr.statusCodes[len(tr.bodies)-1],
+		Header:     make(http.Header),
+		Body:       body,
+		Request:    req,
+	}, nil
+}
+
+func TestRetry_closesBodies(t *testing.T) {
+	transport := &trackingTransport{
+		t:           t,
+		statusCodes: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+	}
+	client, err := NewClient(
+		"https://terrareg.example.com",
+		WithTransport(transport),
+		WithRetryWait(time.Millisecond, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := client.makeRequest(context.Background(), "https://terrareg.example.com/v1/terrareg/git_providers", http.MethodGet, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transport.bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(transport.bodies))
+	}
+
+	// The final response remains readable by the caller
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 1024 {
+		t.Errorf("expected 1024 byte body, got %d bytes", len(body))
+	}
+	closeBody(res)
+
+	for i, body := range transport.bodies {
+		if eof, closed := body.state(); !eof || closed != 1 {
+			t.Errorf("response %d: expected body to be drained and closed once (drained: %t, closed: %d times)", i+1, eof, closed)
+		}
+	}
+}
+
+func TestCloseBody(t *testing.T) {
+	body := newTrackedBody("unread response")
+	closeBody(&http.Response{Body: body})
+	if eof, closed := body.state(); !eof || closed != 1 {
+		t.Errorf("expected body to be drained and closed once (drained: %t, closed: %d times)", eof, closed)
+	}
+
+	// Large bodies are only partially drained before closing
+	body = newTrackedBody(strings.Repeat("x", maxDrainBodyLength+1))
+	closeBody(&http.Response{Body: body})
+	if eof, closed := body.state(); eof || closed != 1 {
+		t.Errorf("expected body to be closed without draining (drained: %t, closed: %d times)", eof, closed)
+	}
+
+	// Missing responses and bodies are ignored
+	closeBody(nil)
+	closeBody(&http.Response{})
+}
+
+// newTestCertificate creates a certificate and PEM encoded certificate and
+// key, signed by the parent, or self-signed if parent is nil.
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *tls.Certificate) (tls.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := template, any(key)
+	if parent != nil {
+		parentCert, parentKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Leaf, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	return cert, certPEM, keyPEM
+}
+
+// newTlsTestServer starts a TLS server, discarding the handshake
+// errors logged by the server for rejected connections.
+func newTlsTestServer(t *testing.T, config *tls.Config, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.TLS = config
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serverCertificatePEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func testTlsRequest(t *testing.T, url string, opts ...Option) error {
+	t.Helper()
+
+	// Disable retries, as TLS errors are not transient
+	client, err := NewClient(url, append(opts, WithMaxRetries(0))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.makeRequest(context.Background(), url, http.MethodGet, nil)
+	if err != nil {
+		return err
+	}
+	closeBody(res)
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func TestTLS_caCertificate(t *testing.T) {
+	server := newTlsTestServer(t, nil, func(w http.ResponseWriter, r *http.Request) {})
+
+	var certErr *tls.CertificateVerificationError
+	if err := testTlsRequest(t, server.URL); !errors.As(err, &certErr) {
+		t.Errorf("expected certificate verification error without CA certificate, got %v", err)
+	}
+	if err := testTlsRequest(t, server.URL, WithCACertificate(serverCertificatePEM(server))); err != nil {
+		t.Errorf("unexpected error with CA certificate: %v", err)
+	}
+
+	// Certificates from another CA are not trusted
+	_, otherCaPEM, _ := newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Other CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	if err := testTlsRequest(t, server.URL, WithCACertificate(otherCaPEM)); !errors.As(err, &certErr) {
+		t.Errorf("expected certificate verification error with other CA certificate, got %v", err)
+	}
+}
+
+func TestTLS_insecureSkipVerify(t *testing.T) {
+	server := newTlsTestServer(t, nil, func(w http.ResponseWriter, r *http.Request) {})
+
+	if err := testTlsRequest(t, server.URL, WithInsecureSkipVerify(true)); err != nil {
+		t.Errorf("unexpected error with verification disabled: %v", err)
+	}
+	if err := testTlsRequest(t, server.URL, WithInsecureSkipVerify(false)); err == nil {
+		t.Error("expected error with verification enabled")
+	}
+}
+
+func TestTLS_clientCertificate(t *testing.T) {
+	clientCa, clientCaPEM, _ := newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Client CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	_, clientCertPEM, clientKeyPEM := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &clientCa)
+	_, untrustedCertPEM, untrustedKeyPEM := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "untrusted"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil)
+
+	var clientCommonName string
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM([]byte(clientCaPEM))
+	server := newTlsTestServer(t, &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		clientCommonName = r.TLS.PeerCertificates[0].Subject.CommonName
+	})
+	caPEM := serverCertificatePEM(server)
+
+	if err := testTlsRequest(t, server.URL, WithCACertificate(caPEM)); err == nil {
+		t.Error("expected error without client certificate")
+	}
+	if err := testTlsRequest(t, server.URL, WithCACertificate(caPEM), WithClientCertificate(untrustedCertPEM, untrustedKeyPEM)); err == nil {
+		t.Error("expected error with untrusted client certificate")
+	}
+	if err := testTlsRequest(t, server.URL, WithCACertificate(caPEM), WithClientCertificate(clientCertPEM, clientKeyPEM)); err != nil {
+		t.Errorf("unexpected error with client certificate: %v", err)
+	}
+	if clientCommonName != "terraform" {
+		t.Errorf("expected client certificate %q, got %q", "terraform", clientCommonName)
+	}
+}
+
+func TestTLS_invalid(t *testing.T) {
+	_, certPEM, keyPEM := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+	}, nil)
+	_, _, otherKeyPEM := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "other"},
+	}, nil)
+
+	tests := map[string][]Option{
+		"invalid CA PEM":          {WithCACertificate("not a certificate")},
+		"CA private key":          {WithCACertificate(keyPEM)},
+		"client certificate only": {WithClientCertificate(certPEM, "")},
+		"client key only":         {WithClientCertificate("", keyPEM)},
+		"invalid client PEM":      {WithClientCertificate("not a certificate", "not a key")},
+		"mismatched client key":   {WithClientCertificate(certPEM, otherKeyPEM)},
+	}
+	for name, opts := range tests {
+		if _, err := NewClient("https://terrareg.example.com", opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if _, err := NewClient("https://terrareg.example.com", WithCACertificate(certPEM), WithClientCertificate(certPEM, keyPEM)); err != nil {
+		t.Errorf("unexpected error with valid certificates: %v", err)
+	}
+}
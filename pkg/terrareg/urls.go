@@ -0,0 +1,55 @@
+package terrareg
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NormalizeUrl validates the URL of a Terrareg instance, returning
+// the URL without a trailing slash.
+//
+// Terrareg may be hosted under a sub-path (e.g. behind a reverse proxy),
+// which is retained as the base path for all API requests.
+func NormalizeUrl(rawUrl string) (string, error) {
+	parsedUrl, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return "", fmt.Errorf("invalid Terrareg URL %q: %w", rawUrl, err)
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return "", fmt.Errorf("invalid Terrareg URL %q: scheme must be http or https", rawUrl)
+	}
+	if parsedUrl.Host == "" {
+		return "", fmt.Errorf("invalid Terrareg URL %q: must contain a host", rawUrl)
+	}
+	if parsedUrl.User != nil {
+		return "", fmt.Errorf("invalid Terrareg URL %q: must not contain credentials", rawUrl)
+	}
+	if parsedUrl.RawQuery != "" || parsedUrl.Fragment != "" {
+		return "", fmt.Errorf("invalid Terrareg URL %q: must not contain a query string or fragment", rawUrl)
+	}
+
+	parsedUrl.Path = strings.TrimRight(parsedUrl.Path, "/")
+	parsedUrl.RawPath = strings.TrimRight(parsedUrl.RawPath, "/")
+	parsedUrl.ForceQuery = false
+
+	return parsedUrl.String(), nil
+}
+
+// urlHost returns the host (and port, if provided) of a URL
+func urlHost(rawUrl string) (string, error) {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	return parsedUrl.Host, nil
+}
+
+// escapePathSegments URL-escapes each path segment and joins them
+func escapePathSegments(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return strings.Join(escaped, "/")
+}